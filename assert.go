@@ -5,56 +5,391 @@ import (
 	"bytes"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
+	"regexp"
+	"runtime/debug"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 	"testing"
+	"time"
+	"unicode/utf8"
 
 	"github.com/alecthomas/repr"
-	"github.com/hexops/gotextdiff"
-	"github.com/hexops/gotextdiff/myers"
+	"golang.org/x/exp/constraints"
 )
 
 // A CompareOption modifies how object comparisons behave.
-type CompareOption func() []repr.Option
+type CompareOption func(o *compareOptions)
+
+var (
+	defaultCompareOptionsLock sync.RWMutex
+	defaultCompareOptions     []CompareOption
+)
+
+// SetDefaultCompareOptions sets options that apply to every comparison, and returns a
+// function that restores the previous defaults, eg.
+//
+//	func TestMain(m *testing.M) {
+//		assert.SetDefaultCompareOptions(assert.Exclude[time.Time]())
+//		os.Exit(m.Run())
+//	}
+//
+// Default options are applied before those passed to an individual assertion, so per-call
+// options take precedence where they conflict. Options that accumulate, such as Exclude or
+// IgnoreCase, cannot be undone per-call. Calling SetDefaultCompareOptions with no options
+// removes the defaults.
+func SetDefaultCompareOptions(options ...CompareOption) (restore func()) {
+	defaultCompareOptionsLock.Lock()
+	defer defaultCompareOptionsLock.Unlock()
+	previous := defaultCompareOptions
+	defaultCompareOptions = options
+	return func() {
+		defaultCompareOptionsLock.Lock()
+		defer defaultCompareOptionsLock.Unlock()
+		defaultCompareOptions = previous
+	}
+}
 
 // Exclude fields of the given type from comparison.
 func Exclude[T any]() CompareOption {
-	return func() []repr.Option {
-		return []repr.Option{repr.Hide[T]()}
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, fmt.Sprintf("Exclude[%s]() ignores fields of type %s", typeOf[T](), typeOf[T]()))
+		o.reprOptions = append(o.reprOptions, repr.Hide[T]())
+		o.exclude[typeOf[T]()] = true
+	}
+}
+
+// ExcludeFields excludes struct fields with the given names from comparison.
+//
+// Fields are matched by name in every struct type at any depth, including through
+// pointers, slices, arrays, maps and interfaces, so a name that is used by more
+// than one struct type is excluded from all of them. Excluded fields are compared
+// as if they held their zero value.
+func ExcludeFields(names ...string) CompareOption {
+	exclude := map[string]bool{}
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		exclude[name] = true
+		quoted[i] = strconv.Quote(name)
+	}
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, fmt.Sprintf("ExcludeFields(%s) ignores fields with these names", strings.Join(quoted, ", ")))
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.Struct {
+				return v
+			}
+			for i := 0; i < v.NumField(); i++ {
+				if exclude[v.Type().Field(i).Name] {
+					field := settable(v.Field(i))
+					field.Set(reflect.Zero(field.Type()))
+				}
+			}
+			return v
+		})
+	}
+}
+
+// IgnoreMapKeys removes entries with the given keys from maps before comparison.
+//
+// This applies to maps at any depth whose keys are of kind string, eg. to ignore
+// "request_id" or "timestamp" entries in decoded API responses. Maps with other key types
+// are left untouched.
+func IgnoreMapKeys(keys ...string) CompareOption {
+	ignore := map[string]bool{}
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		ignore[key] = true
+		quoted[i] = strconv.Quote(key)
+	}
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, fmt.Sprintf("IgnoreMapKeys(%s) ignores map entries with these keys", strings.Join(quoted, ", ")))
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.Map || v.Type().Key().Kind() != reflect.String || v.IsNil() {
+				return v
+			}
+			for _, key := range v.MapKeys() {
+				if ignore[key.String()] {
+					v.SetMapIndex(key, reflect.Value{})
+				}
+			}
+			return v
+		})
+	}
+}
+
+// SortSlices sorts all slices of T using "less" before comparison.
+//
+// This applies to slices of T at any depth, allowing order-insensitive comparison of
+// nested values.
+func SortSlices[T any](less func(a, b T) bool) CompareOption {
+	elem := typeOf[T]()
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, fmt.Sprintf("SortSlices[%s]() ignores the order of elements in slices of %s", elem, elem))
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.Slice || v.Type().Elem() != elem || v.IsNil() {
+				return v
+			}
+			sort.SliceStable(v.Interface(), func(i, j int) bool {
+				return less(as[T](v.Index(i).Interface()), as[T](v.Index(j).Interface()))
+			})
+			return v
+		})
+	}
+}
+
+// WithComparator uses "equal" to compare values of type T.
+//
+// Comparators apply to values at any depth, and to any value whose type is assignable to
+// T, so an interface type may be used to compare all types implementing it. If more than
+// one comparator applies to a value, the first one given is used. Values that a comparator
+// considers equal are treated as identical, including in diffs, and all other values are
+// compared as usual.
+func WithComparator[T any](equal func(a, b T) bool) CompareOption {
+	typ := typeOf[T]()
+	return withComparator(fmt.Sprintf("WithComparator[%s]() compares values of type %s with a custom function", typ, typ), equal)
+}
+
+// withComparator is WithComparator with the description reported by failure messages.
+func withComparator[T any](description string, equal func(a, b T) bool) CompareOption {
+	typ := typeOf[T]()
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, description)
+		o.comparators = append(o.comparators, comparator{
+			applies: func(t reflect.Type) bool { return t.AssignableTo(typ) },
+			equal: func(a, b reflect.Value) bool {
+				return equal(a.Interface().(T), b.Interface().(T))
+			},
+		})
+	}
+}
+
+// DeepCompare compares values field by field, rather than by their string representation.
+//
+// Functions and channels, which are otherwise indistinguishable, are compared by identity,
+// and failures report the path of each differing value, eg. ".Items[2].Name". The Exclude
+// and OmitEmpty options are respected.
+func DeepCompare() CompareOption {
+	return func(o *compareOptions) {
+		o.deepCompare = true
+	}
+}
+
+// DiffPaths reports the paths of up to "limit" differences above the diff when Equal fails,
+// eg. "Difference at .Users[1].Email".
+//
+// Unlike DeepCompare, this does not change how values are compared. Paths are only reported
+// for structs, slices, arrays and maps.
+func DiffPaths(limit int) CompareOption {
+	return func(o *compareOptions) {
+		o.diffPaths = limit
+	}
+}
+
+// ApproxFloat treats floating point values within "delta" of each other as equal, at any depth.
+//
+// As with WithComparator, values within delta of each other are treated as identical,
+// including in diffs.
+func ApproxFloat(delta float64) CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, fmt.Sprintf("ApproxFloat(%v) treats floating point values within %v of each other as equal", delta, delta))
+		o.comparators = append(o.comparators, comparator{
+			applies: func(t reflect.Type) bool {
+				return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+			},
+			equal: func(a, b reflect.Value) bool {
+				x, y := a.Float(), b.Float()
+				return x == y || (math.IsNaN(x) && math.IsNaN(y)) || inDelta(x, y, delta)
+			},
+		})
 	}
 }
 
 // OmitEmpty fields from comparison.
 func OmitEmpty() CompareOption {
-	return func() []repr.Option {
-		return []repr.Option{repr.OmitEmpty(true)}
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "OmitEmpty() ignores empty fields")
+		o.reprOptions = append(o.reprOptions, repr.OmitEmpty(true))
+		o.omitEmpty = true
+	}
+}
+
+// IgnoreCase compares strings case-insensitively.
+//
+// Strings are converted to lower case before comparison at any depth, so diffs will also
+// show lower case strings. Only values of kind string are affected, all other values are
+// left untouched. Map keys are never converted, as keys differing only in case would then
+// collide.
+func IgnoreCase() CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "IgnoreCase() ignores the case of strings")
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.String {
+				return v
+			}
+			return reflect.ValueOf(strings.ToLower(v.String())).Convert(v.Type())
+		})
+	}
+}
+
+// IgnoreLineEndings compares strings with CRLF and CR line endings converted to LF.
+//
+// As with IgnoreCase, strings are converted at any depth, other than map keys.
+func IgnoreLineEndings() CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "IgnoreLineEndings() ignores line endings in strings")
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.String {
+				return v
+			}
+			return reflect.ValueOf(lineEndingReplacer.Replace(v.String())).Convert(v.Type())
+		})
+	}
+}
+
+var lineEndingReplacer = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+
+// IgnoreTrailingSpace compares strings with trailing spaces and tabs removed from each line.
+//
+// As with IgnoreCase, strings are converted at any depth, other than map keys.
+func IgnoreTrailingSpace() CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "IgnoreTrailingSpace() ignores trailing white space on each line of strings")
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.String {
+				return v
+			}
+			lines := strings.Split(v.String(), "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimRight(line, " \t")
+			}
+			return reflect.ValueOf(strings.Join(lines, "\n")).Convert(v.Type())
+		})
+	}
+}
+
+// TrimSpace compares strings with leading and trailing white space removed, as with
+// strings.TrimSpace.
+//
+// As with IgnoreCase, strings are converted at any depth, other than map keys.
+func TrimSpace() CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "TrimSpace() ignores leading and trailing white space in strings")
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() != reflect.String {
+				return v
+			}
+			return reflect.ValueOf(strings.TrimSpace(v.String())).Convert(v.Type())
+		})
+	}
+}
+
+// IgnoreUnexported excludes unexported struct fields from comparison at any depth.
+//
+// This allows comparison of structs embedding eg. a sync.Mutex or sync.Once, whose
+// internal state would otherwise cause spurious differences. Note that values of types
+// holding all of their state in unexported fields, such as big.Int, will then compare
+// equal, with the exception of time.Time.
+func IgnoreUnexported() CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "IgnoreUnexported() ignores unexported fields")
+		o.ignoreUnexported = true
 	}
 }
 
 // IgnoreGoStringer ignores GoStringer implementations when comparing.
 func IgnoreGoStringer() CompareOption {
-	return func() []repr.Option {
-		return []repr.Option{repr.IgnoreGoStringer()}
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "IgnoreGoStringer() ignores GoString methods")
+		o.reprOptions = append(o.reprOptions, repr.IgnoreGoStringer())
+		o.ignoreGoStringer = true
+	}
+}
+
+// ReprOptions renders values with the given repr options before comparing them.
+//
+// Prior to the introduction of options that are not implemented with repr, CompareOption was
+// defined as "func() []repr.Option". Custom options written against that definition can be
+// converted by passing the repr options they returned to ReprOptions.
+func ReprOptions(options ...repr.Option) CompareOption {
+	return func(o *compareOptions) {
+		o.applied = append(o.applied, "ReprOptions() renders values with custom repr options")
+		o.reprOptions = append(o.reprOptions, options...)
 	}
 }
 
+// ErrorsAsValues disables the special handling of errors by Equal and friends, so that
+// errors are compared like any other value rather than with errors.Is and by message.
+func ErrorsAsValues() CompareOption {
+	return func(o *compareOptions) {
+		o.errorsAsValues = true
+	}
+}
+
+// CompareStringer compares values implementing fmt.Stringer, at any depth, by the output of
+// their String methods rather than by their contents.
+//
+// This takes precedence over GoString methods, which are otherwise used to compare values
+// unless IgnoreGoStringer is given, but diffs still display values with GoString or their
+// Go representation.
+func CompareStringer() CompareOption {
+	return withComparator("CompareStringer() compares fmt.Stringer values by their String methods", func(a, b fmt.Stringer) bool {
+		if isNil(a) || isNil(b) {
+			return isNil(a) == isNil(b)
+		}
+		return a.String() == b.String()
+	})
+}
+
+// Transform compares values of type T by the result of passing them to "fn", at any depth,
+// eg. to compare times as UTC strings regardless of their location:
+//
+//	assert.Equal(t, expected, actual, assert.Transform("UTC", func(t time.Time) string {
+//		return t.UTC().Format(time.RFC3339)
+//	}))
+//
+// Transformed values are compared as if by Equal without options. As with WithComparator,
+// the transform applies to any value whose type is assignable to T, and diffs display the
+// untransformed values. The name is used to describe the transform in failure messages.
+func Transform[T, U any](name string, fn func(T) U) CompareOption {
+	typ := typeOf[T]()
+	return withComparator(fmt.Sprintf("Transform(%q) compares values of type %s by their transformed value", name, typ), func(a, b T) bool {
+		return objectsAreEqual(fn(a), fn(b))
+	})
+}
+
 // Compare two values for equality and return true or false.
 func Compare[T any](t testing.TB, x, y T, options ...CompareOption) bool {
 	return objectsAreEqual(x, y, options...)
 }
 
+// CompareDiff compares two values for equality, returning true if they are equal or
+// false and a diff of the two values if they are not.
+func CompareDiff[T any](x, y T, options ...CompareOption) (equal bool, diff string) {
+	if objectsAreEqual(x, y, options...) {
+		return true, ""
+	}
+	return false, Diff(x, y, options...)
+}
+
 func extractCompareOptions(msgAndArgs ...any) ([]any, []CompareOption) {
-	compareOptions := []CompareOption{}
+	return extractOptions[CompareOption](msgAndArgs...)
+}
+
+// extractOptions separates options of type O from the other arguments of an assertion.
+func extractOptions[O any](msgAndArgs ...any) ([]any, []O) {
+	options := []O{}
 	out := []any{}
 	for _, arg := range msgAndArgs {
-		if opt, ok := arg.(CompareOption); ok {
-			compareOptions = append(compareOptions, opt)
+		if opt, ok := arg.(O); ok {
+			options = append(options, opt)
 		} else {
 			out = append(out, arg)
 		}
 	}
-	return out, compareOptions
+	return out, options
 }
 
 // HasPrefix asserts that the string s starts with prefix.
@@ -64,7 +399,7 @@ func HasPrefix(t testing.TB, s, prefix string, msgAndArgs ...any) {
 	}
 	t.Helper()
 	msg := formatMsgAndArgs("Expected string to have prefix:", msgAndArgs...)
-	t.Fatalf("%s\nPrefix: %q\nString: %q\n", msg, prefix, s)
+	fatalf(t, msgAndArgs, "%s\nPrefix: %q\nString: %q\n", msg, prefix, s)
 }
 
 // HasSuffix asserts that the string s ends with suffix.
@@ -74,12 +409,42 @@ func HasSuffix(t testing.TB, s, suffix string, msgAndArgs ...any) {
 	}
 	t.Helper()
 	msg := formatMsgAndArgs("Expected string to have suffix:", msgAndArgs...)
-	t.Fatalf("%s\nSuffix: %q\nString: %q\n", msg, suffix, s)
+	fatalf(t, msgAndArgs, "%s\nSuffix: %q\nString: %q\n", msg, suffix, s)
+}
+
+// HasPrefixFold asserts that the string s starts with prefix, ignoring case.
+func HasPrefixFold(t testing.TB, s, prefix string, msgAndArgs ...any) {
+	if hasPrefixFold(s, prefix) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected string to have prefix:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPrefix: %q\nString: %q\n", msg, prefix, s)
+}
+
+// HasSuffixFold asserts that the string s ends with suffix, ignoring case.
+func HasSuffixFold(t testing.TB, s, suffix string, msgAndArgs ...any) {
+	if hasSuffixFold(s, suffix) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected string to have suffix:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nSuffix: %q\nString: %q\n", msg, suffix, s)
 }
 
 // Equal asserts that "expected" and "actual" are equal.
 //
-// If they are not, a diff of the Go representation of the values will be displayed.
+// If they are not, a diff of the Go representation of the values will be displayed, preceded
+// by their types if these differ.
+//
+// Unexported struct fields are compared like exported ones, unless IgnoreUnexported is given.
+// Numbers from math/big are compared with their Cmp methods at any depth. They are only
+// displayed in decimal form when pointers to them are compared directly: numbers nested in
+// other values are displayed with their internal representation, eg. "abs: big.nat{...}".
+//
+// Non-nil errors are equal if "actual" matches "expected" according to errors.Is, or if
+// they have the same message, and are diffed by their messages. Use ErrorsAsValues to
+// compare them like any other value instead.
 func Equal[T any](t testing.TB, expected, actual T, msgArgsAndCompareOptions ...any) {
 	msgArgsAndCompareOptions, compareOptions := extractCompareOptions(msgArgsAndCompareOptions...)
 	if objectsAreEqual(expected, actual, compareOptions...) {
@@ -87,12 +452,17 @@ func Equal[T any](t testing.TB, expected, actual T, msgArgsAndCompareOptions ...
 	}
 	t.Helper()
 	msg := formatMsgAndArgs("Expected values to be equal:", msgArgsAndCompareOptions...)
-	t.Fatalf("%s\n%s", msg, Diff(expected, actual, compareOptions...))
+	diff := Diff(expected, actual, compareOptions...)
+	msg = fmt.Sprintf("%s\n%s%s%s%s", msg, typeMismatch(expected, actual, compareOptions...), ignorableDifference(expected, actual, compareOptions...),
+		differences(expected, actual, compareOptions...), colorDiff(diff))
+	fatalCompare(t, msgArgsAndCompareOptions, expected, actual, diff, msg)
 }
 
 // NotEqual asserts that "expected" is not equal to "actual".
 //
-// If they are equal the expected value will be displayed.
+// If they are equal the expected value will be displayed, along with any compare options
+// that may have caused them to be equal and the differences between the values that the
+// options ignored.
 func NotEqual[T any](t testing.TB, expected, actual T, msgArgsAndCompareOptions ...any) {
 	msgArgsAndCompareOptions, compareOptions := extractCompareOptions(msgArgsAndCompareOptions...)
 	if !objectsAreEqual(expected, actual, compareOptions...) {
@@ -100,7 +470,34 @@ func NotEqual[T any](t testing.TB, expected, actual T, msgArgsAndCompareOptions
 	}
 	t.Helper()
 	msg := formatMsgAndArgs("Expected values to not be equal but both were:", msgArgsAndCompareOptions...)
-	t.Fatalf("%s\n%s", msg, repr.String(expected, repr.Indent("  ")))
+	msg = fmt.Sprintf("%s\n%s", msg, repr.String(expected, repr.Indent("  ")))
+	if applied := expandCompareOptions(compareOptions...).applied; len(applied) > 0 {
+		msg += fmt.Sprintf("\nCompare options applied:\n  %s\n", strings.Join(applied, "\n  "))
+		if diff := rawDiff(expected, actual); diff != "" {
+			msg += "Differences ignored:\n" + colorDiff(diff)
+		}
+	}
+	fatalCompare(t, msgArgsAndCompareOptions, expected, actual, "", msg)
+}
+
+// Same asserts that "expected" and "actual" point to the same object.
+func Same[T any](t testing.TB, expected, actual *T, msgAndArgs ...any) {
+	if expected == actual {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected pointers to be the same:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected: %p\nActual: %p\n", msg, expected, actual)
+}
+
+// NotSame asserts that "expected" and "actual" do not point to the same object.
+func NotSame[T any](t testing.TB, expected, actual *T, msgAndArgs ...any) {
+	if expected != actual {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected pointers to not be the same but both were:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%p", msg, expected)
 }
 
 // Contains asserts that "haystack" contains "needle".
@@ -110,7 +507,7 @@ func Contains(t testing.TB, haystack string, needle string, msgAndArgs ...any) {
 	}
 	t.Helper()
 	msg := formatMsgAndArgs("Haystack does not contain needle.", msgAndArgs...)
-	t.Fatalf("%s\nNeedle: %q\nHaystack: %q\n", msg, needle, haystack)
+	fatalf(t, msgAndArgs, "%s\nNeedle: %q\nHaystack: %q\n", msg, needle, haystack)
 }
 
 // NotContains asserts that "haystack" does not contain "needle".
@@ -121,7 +518,113 @@ func NotContains(t testing.TB, haystack string, needle string, msgAndArgs ...any
 	t.Helper()
 	msg := formatMsgAndArgs("Haystack should not contain needle.", msgAndArgs...)
 	quotedHaystack, quotedNeedle, positions := needlePosition(haystack, needle)
-	t.Fatalf("%s\nNeedle: %s\nHaystack: %s\n          %s\n", msg, quotedNeedle, quotedHaystack, positions)
+	fatalf(t, msgAndArgs, "%s\nNeedle: %s\nHaystack: %s\n          %s\n", msg, quotedNeedle, quotedHaystack, positions)
+}
+
+// ContainsAll asserts that "haystack" contains every one of "needles", reporting all of
+// those that are missing.
+func ContainsAll(t testing.TB, haystack string, needles []string, msgAndArgs ...any) {
+	missing := []string{}
+	for _, needle := range needles {
+		if !strings.Contains(haystack, needle) {
+			missing = append(missing, needle)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Haystack does not contain all needles.", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nMissing: %q\nHaystack: %q\n", msg, missing, haystack)
+}
+
+// ContainsAny asserts that "haystack" contains at least one of "needles".
+func ContainsAny(t testing.TB, haystack string, needles []string, msgAndArgs ...any) {
+	for _, needle := range needles {
+		if strings.Contains(haystack, needle) {
+			return
+		}
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Haystack does not contain any needle.", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nNeedles: %q\nHaystack: %q\n", msg, needles, haystack)
+}
+
+// ContainsN asserts that "haystack" contains exactly "n" non-overlapping instances of "needle".
+func ContainsN(t testing.TB, haystack string, needle string, n int, msgAndArgs ...any) {
+	count := strings.Count(haystack, needle)
+	if count == n {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs(fmt.Sprintf("Expected haystack to contain needle %d times but found %d.", n, count), msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nNeedle: %q\nHaystack: %q\n", msg, needle, haystack)
+}
+
+// BytesContains asserts that "haystack" contains "needle".
+func BytesContains(t testing.TB, haystack, needle []byte, msgAndArgs ...any) {
+	if bytes.Contains(haystack, needle) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Haystack does not contain needle.", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nNeedle:\n%sHaystack:\n%s", msg, hexDump(needle, 0, len(needle), 0, 0), hexDump(haystack, 0, len(haystack), 0, 0))
+}
+
+// NotBytesContains asserts that "haystack" does not contain "needle".
+func NotBytesContains(t testing.TB, haystack, needle []byte, msgAndArgs ...any) {
+	index := bytes.Index(haystack, needle)
+	if index == -1 {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Haystack should not contain needle.", msgAndArgs...)
+	end := index + len(needle)
+	// Show the rows containing the needle, plus one row of context either side.
+	from := index - index%hexDumpWidth - hexDumpWidth
+	to := index + len(needle) - 1
+	to = to - to%hexDumpWidth + 2*hexDumpWidth
+	fatalf(t, msgAndArgs, "%s\nNeedle:\n%sHaystack at offset %#x:\n%s", msg, hexDump(needle, 0, len(needle), 0, 0), index, hexDump(haystack, from, to, index, end))
+}
+
+// A Pattern is either a regular expression string or a compiled *regexp.Regexp.
+type Pattern interface {
+	string | *regexp.Regexp
+}
+
+// Regexp asserts that the string s matches the regular expression "pattern".
+func Regexp[P Pattern](t testing.TB, pattern P, s string, msgAndArgs ...any) {
+	re, err := compilePattern(pattern)
+	if err == nil && re.MatchString(s) {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		fatalf(t, msgAndArgs, "%s\n%s", formatMsgAndArgs("Invalid regexp pattern:", msgAndArgs...), err)
+		return
+	}
+	msg := formatMsgAndArgs("Expected string to match pattern:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPattern: %s\nString: %q\n", msg, re, s)
+}
+
+// NotRegexp asserts that the string s does not match the regular expression "pattern".
+func NotRegexp[P Pattern](t testing.TB, pattern P, s string, msgAndArgs ...any) {
+	re, err := compilePattern(pattern)
+	var loc []int
+	if err == nil {
+		loc = re.FindStringIndex(s)
+		if loc == nil {
+			return
+		}
+	}
+	t.Helper()
+	if err != nil {
+		fatalf(t, msgAndArgs, "%s\n%s", formatMsgAndArgs("Invalid regexp pattern:", msgAndArgs...), err)
+		return
+	}
+	msg := formatMsgAndArgs("Expected string to not match pattern:", msgAndArgs...)
+	quotedString, positions := matchPosition(s, loc[0], loc[1])
+	fatalf(t, msgAndArgs, "%s\nPattern: %s\nString: %s\n        %s\n", msg, re, quotedString, positions)
 }
 
 // SliceContains asserts that "haystack" contains "needle".
@@ -136,7 +639,25 @@ func SliceContains[T any](t testing.TB, haystack []T, needle T, msgAndArgs ...in
 	msg := formatMsgAndArgs("Haystack does not contain needle.", msgAndArgs...)
 	needleRepr := repr.String(needle, repr.Indent("  "))
 	haystackRepr := repr.String(haystack, repr.Indent("  "))
-	t.Fatalf("%s\nNeedle: %s\nHaystack: %s\n", msg, needleRepr, haystackRepr)
+	if len(haystack) == 0 {
+		fatalf(t, msgAndArgs, "%s\nNeedle: %s\nHaystack: %s\n", msg, needleRepr, haystackRepr)
+		return
+	}
+	closest := closestElement(haystack, needle)
+	fatalf(t, msgAndArgs, "%s\nNeedle: %s\nHaystack: %s\nClosest element [%d]:\n%s%s", msg, needleRepr, haystackRepr,
+		closest, typeMismatch(needle, haystack[closest]), colorDiff(Diff(needle, haystack[closest])))
+}
+
+// closestElement returns the index of the element of the non-empty "haystack" with the
+// smallest diff against "needle".
+func closestElement[T any](haystack []T, needle T) int {
+	closest, closestSize := 0, -1
+	for i, item := range haystack {
+		if size := diffSize(needle, item); closestSize == -1 || size < closestSize {
+			closest, closestSize = i, size
+		}
+	}
+	return closest
 }
 
 // NotSliceContains asserts that "haystack" does not contain "needle".
@@ -147,190 +668,1620 @@ func NotSliceContains[T any](t testing.TB, haystack []T, needle T, msgAndArgs ..
 			msg := formatMsgAndArgs("Haystack should not contain needle.", msgAndArgs...)
 			needleRepr := repr.String(needle, repr.Indent("  "))
 			haystackRepr := repr.String(haystack, repr.Indent("  "))
-			t.Fatalf("%s\nNeedle: %s\nHaystack: %s\n", msg, needleRepr, haystackRepr)
+			fatalf(t, msgAndArgs, "%s\nNeedle: %s\nHaystack: %s\n", msg, needleRepr, haystackRepr)
+			return
 		}
 	}
 }
 
-// Zero asserts that a value is its zero value.
-func Zero[T any](t testing.TB, value T, msgAndArgs ...any) {
-	var zero T
-	if objectsAreEqual(value, zero) {
+// SliceContainsN asserts that "haystack" contains exactly "n" elements equal to "needle".
+func SliceContainsN[T any](t testing.TB, haystack []T, needle T, n int, msgAndArgs ...any) {
+	count := 0
+	for _, item := range haystack {
+		if objectsAreEqual(item, needle) {
+			count++
+		}
+	}
+	if count == n {
 		return
 	}
-	val := reflect.ValueOf(value)
-	if (val.Kind() == reflect.Slice || val.Kind() == reflect.Map || val.Kind() == reflect.Array) && val.Len() == 0 {
+	t.Helper()
+	msg := formatMsgAndArgs(fmt.Sprintf("Expected haystack to contain needle %d times but found %d.", n, count), msgAndArgs...)
+	needleRepr := repr.String(needle, repr.Indent("  "))
+	haystackRepr := repr.String(haystack, repr.Indent("  "))
+	fatalf(t, msgAndArgs, "%s\nNeedle: %s\nHaystack: %s\n", msg, needleRepr, haystackRepr)
+}
+
+// MapContainsKey asserts that the map "m" contains "key".
+func MapContainsKey[K comparable, V any](t testing.TB, m map[K]V, key K, msgAndArgs ...any) {
+	if _, ok := m[key]; ok {
 		return
 	}
 	t.Helper()
-	msg := formatMsgAndArgs("Expected a zero value but got:", msgAndArgs...)
-	t.Fatalf("%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+	msg := formatMsgAndArgs("Map does not contain key.", msgAndArgs...)
+	keyRepr := repr.String(key, repr.Indent("  "))
+	mapRepr := repr.String(m, repr.Indent("  "))
+	fatalf(t, msgAndArgs, "%s\nKey: %s\nMap: %s\n", msg, keyRepr, mapRepr)
 }
 
-// NotZero asserts that a value is not its zero value.
-func NotZero[T any](t testing.TB, value T, msgAndArgs ...any) {
-	var zero T
-	if !objectsAreEqual(value, zero) {
-		val := reflect.ValueOf(value)
-		if !((val.Kind() == reflect.Slice || val.Kind() == reflect.Map || val.Kind() == reflect.Array) && val.Len() == 0) {
+// MapContainsValue asserts that the map "m" contains "value".
+func MapContainsValue[K comparable, V any](t testing.TB, m map[K]V, value V, msgAndArgs ...any) {
+	for _, v := range m {
+		if objectsAreEqual(v, value) {
 			return
 		}
 	}
 	t.Helper()
-	msg := formatMsgAndArgs("Did not expect the zero value:", msgAndArgs...)
-	t.Fatalf("%s\n%s", msg, repr.String(value))
+	msg := formatMsgAndArgs("Map does not contain value.", msgAndArgs...)
+	valueRepr := repr.String(value, repr.Indent("  "))
+	mapRepr := repr.String(m, repr.Indent("  "))
+	fatalf(t, msgAndArgs, "%s\nValue: %s\nMap: %s\n", msg, valueRepr, mapRepr)
 }
 
-// EqualError asserts that either an error is non-nil and that its message is what is expected,
-// or that error is nil if the expected message is empty.
-func EqualError(t testing.TB, err error, errString string, msgAndArgs ...any) {
-	if err == nil && errString == "" {
+// MapContains asserts that every key in "subset" is present in "m" with an equal value.
+//
+// On failure, keys missing from "m" are listed, followed by a diff of the values of each key
+// that differs.
+func MapContains[K comparable, V any](t testing.TB, m map[K]V, subset map[K]V, msgArgsAndCompareOptions ...any) {
+	msgArgsAndCompareOptions, compareOptions := extractCompareOptions(msgArgsAndCompareOptions...)
+	missing, _, changed := diffMapKeys(subset, m, compareOptions...)
+	if len(missing) == 0 && len(changed) == 0 {
 		return
 	}
 	t.Helper()
-	if err == nil {
-		t.Fatal(formatMsgAndArgs("Expected an error", msgAndArgs...))
+	w := &strings.Builder{}
+	w.WriteString(formatMsgAndArgs("Map does not contain subset:", msgArgsAndCompareOptions...))
+	if len(missing) > 0 {
+		w.WriteString("\nMissing keys:")
+		for _, k := range missing {
+			fmt.Fprintf(w, "\n  %s: %s", repr.String(k), indent(repr.String(subset[k], repr.Indent("  ")), "  "))
+		}
 	}
-	if err.Error() != errString {
-		msg := formatMsgAndArgs("Error message not as expected:", msgAndArgs...)
-		t.Fatalf("%s\n%s", msg, Diff(errString, err.Error()))
+	if len(changed) > 0 {
+		w.WriteString("\nDifferent values:")
+		for _, k := range changed {
+			diff := strings.TrimSuffix(colorDiff(Diff(subset[k], m[k], compareOptions...)), "\n")
+			fmt.Fprintf(w, "\n  %s:\n    %s", repr.String(k), indent(diff, "    "))
+		}
 	}
+	fatalf(t, msgArgsAndCompareOptions, "%s\n", w.String())
 }
 
-// IsError asserts than any error in "err"'s tree matches "target".
-func IsError(t testing.TB, err, target error, msgAndArgs ...any) {
-	if errors.Is(err, target) {
+// MapEqual asserts that the maps "expected" and "actual" are equal.
+//
+// Maps are compared key by key, so insertion order never affects the comparison and a nil
+// map is equal to an empty map. On failure, keys present in only one of the maps are listed,
+// followed by a diff of the values of each key that differs.
+func MapEqual[K comparable, V any](t testing.TB, expected, actual map[K]V, msgArgsAndCompareOptions ...any) {
+	msgArgsAndCompareOptions, compareOptions := extractCompareOptions(msgArgsAndCompareOptions...)
+	missing, extra, changed := diffMapKeys(expected, actual, compareOptions...)
+	if len(missing) == 0 && len(extra) == 0 && len(changed) == 0 {
 		return
 	}
 	t.Helper()
-	t.Fatal(formatMsgAndArgs(fmt.Sprintf("Error tree %+v should contain error %q", err, target), msgAndArgs...))
+	w := &strings.Builder{}
+	w.WriteString(formatMsgAndArgs("Expected maps to be equal:", msgArgsAndCompareOptions...))
+	if len(missing) > 0 {
+		w.WriteString("\nOnly in expected:")
+		for _, k := range missing {
+			fmt.Fprintf(w, "\n  %s: %s", repr.String(k), indent(repr.String(expected[k], repr.Indent("  ")), "  "))
+		}
+	}
+	if len(extra) > 0 {
+		w.WriteString("\nOnly in actual:")
+		for _, k := range extra {
+			fmt.Fprintf(w, "\n  %s: %s", repr.String(k), indent(repr.String(actual[k], repr.Indent("  ")), "  "))
+		}
+	}
+	if len(changed) > 0 {
+		w.WriteString("\nDifferent values:")
+		for _, k := range changed {
+			diff := strings.TrimSuffix(colorDiff(Diff(expected[k], actual[k], compareOptions...)), "\n")
+			fmt.Fprintf(w, "\n  %s:\n    %s", repr.String(k), indent(diff, "    "))
+		}
+	}
+	fatalf(t, msgArgsAndCompareOptions, "%s\n", w.String())
 }
 
-// NotIsError asserts than no error in "err"'s tree matches "target".
-func NotIsError(t testing.TB, err, target error, msgAndArgs ...any) {
-	if !errors.Is(err, target) {
+// ElementsMatch asserts that "expected" and "actual" contain the same elements, ignoring order.
+//
+// Duplicate elements must occur the same number of times in both slices.
+func ElementsMatch[T any](t testing.TB, expected, actual []T, msgAndArgs ...any) {
+	missing, extra := diffElements(expected, actual)
+	if len(missing) == 0 && len(extra) == 0 {
 		return
 	}
 	t.Helper()
-	t.Fatal(formatMsgAndArgs(fmt.Sprintf("Error tree %+v should NOT contain error %q", err, target), msgAndArgs...))
+	msg := formatMsgAndArgs("Expected elements to match:", msgAndArgs...)
+	missingRepr := repr.String(missing, repr.Indent("  "))
+	extraRepr := repr.String(extra, repr.Indent("  "))
+	fatalf(t, msgAndArgs, "%s\nMissing: %s\nExtra: %s\n", msg, missingRepr, extraRepr)
 }
 
-// Error asserts that an error is not nil.
-func Error(t testing.TB, err error, msgAndArgs ...any) {
-	if err != nil {
-		return
+// Permutation asserts that "actual" is a permutation of "expected", and returns the index
+// in "actual" of each element of "expected".
+//
+// Duplicate elements are matched in order, so the first occurrence in "expected" maps to
+// the first occurrence in "actual", and so on.
+func Permutation[T any](t testing.TB, expected, actual []T, msgAndArgs ...any) []int {
+	mapping, used := matchElements(expected, actual)
+	missing, extra := unmatchedElements(expected, actual, mapping, used)
+	if len(missing) == 0 && len(extra) == 0 {
+		return mapping
 	}
 	t.Helper()
-	t.Fatal(formatMsgAndArgs("Expected an error", msgAndArgs...))
+	msg := formatMsgAndArgs("Expected a permutation:", msgAndArgs...)
+	missingRepr := repr.String(missing, repr.Indent("  "))
+	extraRepr := repr.String(extra, repr.Indent("  "))
+	fatalf(t, msgAndArgs, "%s\nMissing: %s\nExtra: %s\n", msg, missingRepr, extraRepr)
+	return nil
 }
 
-// NoError asserts that an error is nil.
-func NoError(t testing.TB, err error, msgAndArgs ...any) {
-	if err == nil {
+// Subset asserts that every element of "subset" is also in "list".
+func Subset[T any](t testing.TB, list, subset []T, msgAndArgs ...any) {
+	missing := missingElements(list, subset)
+	if len(missing) == 0 {
 		return
 	}
 	t.Helper()
-	msg := formatMsgAndArgs("Did not expect an error but got:", msgAndArgs...)
-	t.Fatalf("%s\n%+v", msg, err)
+	msg := formatMsgAndArgs("Expected list to contain all elements of subset:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nMissing: %s\n", msg, repr.String(missing, repr.Indent("  ")))
 }
 
-// True asserts that an expression is true.
-func True(t testing.TB, ok bool, msgAndArgs ...any) {
-	if ok {
+// Superset asserts that every element of "list" is also in "superset".
+func Superset[T any](t testing.TB, list, superset []T, msgAndArgs ...any) {
+	missing := missingElements(superset, list)
+	if len(missing) == 0 {
 		return
 	}
 	t.Helper()
-	t.Fatal(formatMsgAndArgs("Expected expression to be true", msgAndArgs...))
+	msg := formatMsgAndArgs("Expected superset to contain all elements of list:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nMissing: %s\n", msg, repr.String(missing, repr.Indent("  ")))
 }
 
-// False asserts that an expression is false.
-func False(t testing.TB, ok bool, msgAndArgs ...any) {
-	if !ok {
+// AllMatch asserts that "pred" returns true for every element of "list".
+func AllMatch[T any](t testing.TB, list []T, pred func(T) bool, msgAndArgs ...any) {
+	for i, v := range list {
+		if pred(v) {
+			continue
+		}
+		t.Helper()
+		msg := formatMsgAndArgs("Expected all elements to match predicate:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nElement %d: %s\nList: %s\n", msg, i, repr.String(v, repr.Indent("  ")), repr.String(list, repr.Indent("  ")))
 		return
 	}
-	t.Helper()
-	t.Fatal(formatMsgAndArgs("Expected expression to be false", msgAndArgs...))
 }
 
-// Panics asserts that the given function panics.
-func Panics(t testing.TB, fn func(), msgAndArgs ...any) {
-	t.Helper()
-	defer func() {
-		if recover() == nil {
-			msg := formatMsgAndArgs("Expected function to panic", msgAndArgs...)
-			t.Fatal(msg)
+// EachEqual asserts that every element of "list" is equal to "expected", as with Equal.
+//
+// An empty list passes. On failure, the first element that differs is reported with a diff
+// against "expected".
+func EachEqual[T any](t testing.TB, list []T, expected T, msgArgsAndCompareOptions ...any) {
+	msgArgsAndCompareOptions, compareOptions := extractCompareOptions(msgArgsAndCompareOptions...)
+	for i, v := range list {
+		if objectsAreEqual(expected, v, compareOptions...) {
+			continue
 		}
-	}()
-	fn()
+		t.Helper()
+		msg := formatMsgAndArgs("Expected all elements to be equal to expected value:", msgArgsAndCompareOptions...)
+		diff := Diff(expected, v, compareOptions...)
+		fatalCompare(t, msgArgsAndCompareOptions, expected, v, diff, fmt.Sprintf("%s\nElement %d of %d differs:\n%s", msg, i, len(list), colorDiff(diff)))
+		return
+	}
 }
 
-// NotPanics asserts that the given function does not panic.
-func NotPanics(t testing.TB, fn func(), msgAndArgs ...any) {
-	t.Helper()
-	defer func() {
-		if err := recover(); err != nil {
-			msg := formatMsgAndArgs("Expected function not to panic", msgAndArgs...)
-			t.Fatalf("%s\nPanic: %v", msg, err)
+// AnyMatch asserts that "pred" returns true for at least one element of "list".
+func AnyMatch[T any](t testing.TB, list []T, pred func(T) bool, msgAndArgs ...any) {
+	for _, v := range list {
+		if pred(v) {
+			return
 		}
-	}()
-	fn()
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected at least one element to match predicate:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nList: %s\n", msg, repr.String(list, repr.Indent("  ")))
 }
 
-// Diff returns a unified diff of the string representation of two values.
-func Diff[T any](before, after T, compareOptions ...CompareOption) string {
-	var lhss, rhss string
-	// Special case strings so we get nice diffs.
-	if l, ok := any(before).(string); ok {
-		lhss = l + "\n"
-		rhss = any(after).(string) + "\n"
-	} else {
-		ropts := expandCompareOptions(compareOptions...)
-		lhss = repr.String(before, ropts...) + "\n"
-		rhss = repr.String(after, ropts...) + "\n"
-	}
-	edits := myers.ComputeEdits("a.txt", lhss, rhss)
-	lines := strings.Split(fmt.Sprint(gotextdiff.ToUnified("expected.txt", "actual.txt", lhss, edits)), "\n")
-	if len(lines) < 3 {
-		return ""
-	}
-	return strings.Join(lines[3:], "\n")
+// Unique asserts that no element of "list" occurs more than once.
+func Unique[T comparable](t testing.TB, list []T, msgAndArgs ...any) {
+	t.Helper()
+	UniqueFunc(t, list, func(v T) T { return v }, msgAndArgs...)
+}
+
+// UniqueFunc asserts that no two elements of "list" have the same key, as returned by "key".
+func UniqueFunc[T any, K comparable](t testing.TB, list []T, key func(T) K, msgAndArgs ...any) {
+	first := map[K]int{}
+	for i, v := range list {
+		k := key(v)
+		j, ok := first[k]
+		if !ok {
+			first[k] = i
+			continue
+		}
+		indices := []int{j}
+		for n := j + 1; n < len(list); n++ {
+			if key(list[n]) == k {
+				indices = append(indices, n)
+			}
+		}
+		t.Helper()
+		msg := formatMsgAndArgs("Expected list elements to be unique:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nDuplicate: %s\nIndices: %v\n", msg, repr.String(k), indices)
+		return
+	}
+}
+
+// Sorted asserts that "list" is sorted in non-decreasing order.
+func Sorted[T constraints.Ordered](t testing.TB, list []T, msgAndArgs ...any) {
+	t.Helper()
+	SortedFunc(t, list, func(a, b T) bool { return a < b }, msgAndArgs...)
+}
+
+// SortedFunc asserts that "list" is sorted in non-decreasing order according to "less".
+func SortedFunc[T any](t testing.TB, list []T, less func(a, b T) bool, msgAndArgs ...any) {
+	for i := 1; i < len(list); i++ {
+		if !less(list[i], list[i-1]) {
+			continue
+		}
+		t.Helper()
+		msg := formatMsgAndArgs("Expected list to be sorted:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nElement %d: %s\nis less than element %d: %s\n", msg, i, repr.String(list[i]), i-1, repr.String(list[i-1]))
+		return
+	}
+}
+
+// Zero asserts that a value is its zero value.
+func Zero[T any](t testing.TB, value T, msgAndArgs ...any) {
+	var zero T
+	if objectsAreEqual(value, zero) {
+		return
+	}
+	val := reflect.ValueOf(value)
+	if (val.Kind() == reflect.Slice || val.Kind() == reflect.Map || val.Kind() == reflect.Array) && val.Len() == 0 {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected a zero value but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+}
+
+// NotZero asserts that a value is not its zero value.
+func NotZero[T any](t testing.TB, value T, msgAndArgs ...any) {
+	var zero T
+	if !objectsAreEqual(value, zero) {
+		val := reflect.ValueOf(value)
+		if !((val.Kind() == reflect.Slice || val.Kind() == reflect.Map || val.Kind() == reflect.Array) && val.Len() == 0) {
+			return
+		}
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Did not expect the zero value:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value))
+}
+
+// Len asserts that a slice, array, map, string or channel has the given length.
+func Len[T any](t testing.TB, collection T, length int, msgAndArgs ...any) {
+	actual, ok := lengthOf(collection)
+	if ok && actual == length {
+		return
+	}
+	t.Helper()
+	if !ok {
+		msg := formatMsgAndArgs("Expected a value with a length but got:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(collection, repr.Indent("  ")))
+		return
+	}
+	msg := formatMsgAndArgs("Expected collection to have length:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected: %d\nActual: %d\nCollection: %s\n", msg, length, actual, repr.String(collection, repr.Indent("  ")))
+}
+
+// LenBetween asserts that a slice, array, map, string or channel has a length in the
+// inclusive range ["lo", "hi"].
+func LenBetween[T any](t testing.TB, collection T, lo, hi int, msgAndArgs ...any) {
+	actual, ok := lengthOf(collection)
+	if ok && lo <= hi && lo <= actual && actual <= hi {
+		return
+	}
+	t.Helper()
+	if lo > hi {
+		msg := formatMsgAndArgs("Invalid range, lower bound is greater than upper bound:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nLower bound: %d\nUpper bound: %d\n", msg, lo, hi)
+		return
+	}
+	if !ok {
+		msg := formatMsgAndArgs("Expected a value with a length but got:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(collection, repr.Indent("  ")))
+		return
+	}
+	msg := formatMsgAndArgs("Expected collection length to be in range:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nRange: [%d, %d]\nActual: %d\nCollection: %s\n", msg, lo, hi, actual, repr.String(collection, repr.Indent("  ")))
+}
+
+// Empty asserts that a value is empty.
+//
+// Strings, slices, arrays, maps and channels are empty if their length is zero, and pointers,
+// funcs and interfaces are empty if they are nil. Unlike Zero, any other value, including a
+// zero-valued struct, is not empty.
+func Empty[T any](t testing.TB, value T, msgAndArgs ...any) {
+	if isEmpty(value) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected an empty value but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+}
+
+// NotEmpty asserts that a value is not empty.
+//
+// See Empty for the definition of empty.
+func NotEmpty[T any](t testing.TB, value T, msgAndArgs ...any) {
+	if !isEmpty(value) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected a non-empty value but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+}
+
+// Nil asserts that a value is nil.
+//
+// Unlike a direct comparison with nil, this also treats an interface holding a
+// typed nil pointer, map, slice, channel or func as nil.
+func Nil(t testing.TB, value any, msgAndArgs ...any) {
+	if isNil(value) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected nil but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+}
+
+// NotNil asserts that a value is not nil.
+//
+// An interface holding a typed nil pointer, map, slice, channel or func is
+// considered nil.
+func NotNil(t testing.TB, value any, msgAndArgs ...any) {
+	if !isNil(value) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected a non-nil value but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, nilRepr(value))
+}
+
+// IsType asserts that the dynamic type of "value" is exactly T.
+func IsType[T any](t testing.TB, value any, msgAndArgs ...any) {
+	expected := typeOf[T]()
+	actual := reflect.TypeOf(value)
+	if actual == expected {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected value to be of type:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected: %s\nActual: %s\n", msg, expected, typeName(actual))
+}
+
+// Implements asserts that the dynamic type of "value" implements the interface I.
+//
+// This is a runtime check for dynamic values. Go does not allow a type parameter to be used
+// as a constraint, so there is no generic equivalent that fails to compile. To assert at
+// compile time that a static type implements an interface, use a conversion at package
+// scope instead, eg.
+//
+//	var _ io.Reader = (*MyType)(nil)
+func Implements[I any](t testing.TB, value any, msgAndArgs ...any) {
+	iface := typeOf[I]()
+	if iface.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("type parameter to assert.Implements must be an interface, not %s", iface))
+	}
+	actual := reflect.TypeOf(value)
+	if actual != nil && actual.Implements(iface) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected value to implement interface:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nInterface: %s\nType: %s\n", msg, iface, typeName(actual))
+}
+
+// Greater asserts that a is greater than b.
+func Greater[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) {
+	if a > b {
+		return
+	}
+	t.Helper()
+	failOrdering(t, a, ">", b, msgAndArgs...)
+}
+
+// GreaterOrEqual asserts that a is greater than or equal to b.
+func GreaterOrEqual[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) {
+	if a >= b {
+		return
+	}
+	t.Helper()
+	failOrdering(t, a, ">=", b, msgAndArgs...)
+}
+
+// Less asserts that a is less than b.
+func Less[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) {
+	if a < b {
+		return
+	}
+	t.Helper()
+	failOrdering(t, a, "<", b, msgAndArgs...)
+}
+
+// LessOrEqual asserts that a is less than or equal to b.
+func LessOrEqual[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) {
+	if a <= b {
+		return
+	}
+	t.Helper()
+	failOrdering(t, a, "<=", b, msgAndArgs...)
+}
+
+// CompareOrdered asserts that the three-way comparison "cmp(a, b)" has the sign of "want",
+// which must be -1, 0 or 1.
+//
+// This allows orderings to be asserted for types that are not constraints.Ordered but have
+// a comparison function, eg.
+//
+//	assert.CompareOrdered(t, x, y, (*big.Int).Cmp, -1)
+func CompareOrdered[T any](t testing.TB, a, b T, cmp func(a, b T) int, want int, msgAndArgs ...any) {
+	if want < -1 || want > 1 {
+		t.Helper()
+		msg := formatMsgAndArgs("Invalid comparison result, must be -1, 0 or 1:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nWant: %d\n", msg, want)
+		return
+	}
+	result := cmp(a, b)
+	if sign(result) == want {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Ordering assertion failed:", msgAndArgs...)
+	op := [...]string{"<", "==", ">"}[want+1]
+	fatalf(t, msgAndArgs, "%s\nExpected %s %s %s\nComparison: %d", msg, repr.String(a), op, repr.String(b), result)
+}
+
+// Between asserts that "lo" <= "value" <= "hi".
+func Between[T constraints.Ordered](t testing.TB, value, lo, hi T, msgAndArgs ...any) {
+	if lo <= hi && lo <= value && value <= hi {
+		return
+	}
+	t.Helper()
+	failBetween(t, value, lo, hi, "[%s, %s]", msgAndArgs...)
+}
+
+// BetweenExclusive asserts that "lo" < "value" < "hi".
+func BetweenExclusive[T constraints.Ordered](t testing.TB, value, lo, hi T, msgAndArgs ...any) {
+	if lo <= hi && lo < value && value < hi {
+		return
+	}
+	t.Helper()
+	failBetween(t, value, lo, hi, "(%s, %s)", msgAndArgs...)
+}
+
+// Positive asserts that a value is greater than zero.
+func Positive[T constraints.Signed | constraints.Float](t testing.TB, value T, msgAndArgs ...any) {
+	if value > 0 {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected a positive value but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value))
+}
+
+// Negative asserts that a value is less than zero.
+func Negative[T constraints.Signed | constraints.Float](t testing.TB, value T, msgAndArgs ...any) {
+	if value < 0 {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected a negative value but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value))
+}
+
+// InDelta asserts that "expected" and "actual" are within "delta" of each other.
+//
+// NaN is never within delta of anything, and infinities are only within delta of an
+// infinity of the same sign.
+func InDelta(t testing.TB, expected, actual, delta float64, msgAndArgs ...any) {
+	if inDelta(expected, actual, delta) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected values to be within delta:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected: %v\nActual: %v\nDifference: %v\nDelta: %v\n", msg, expected, actual, math.Abs(expected-actual), delta)
+}
+
+// FloatEqual asserts that "expected" and "actual" are the same floating point value.
+//
+// Unlike ==, NaN is equal to NaN regardless of its payload, which suits code where NaN
+// propagation is part of the contract. Positive and negative zero are equal. Any other
+// values must be exactly equal. On failure the bit patterns of both values are displayed,
+// to distinguish values that print identically.
+func FloatEqual(t testing.TB, expected, actual float64, msgAndArgs ...any) {
+	if expected == actual || (math.IsNaN(expected) && math.IsNaN(actual)) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected floats to be equal:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected: %v (0x%016x)\nActual:   %v (0x%016x)\n", msg,
+		expected, math.Float64bits(expected), actual, math.Float64bits(actual))
+}
+
+// SliceInDelta asserts that "expected" and "actual" have the same length, and that each
+// element of "actual" is within "delta" of the corresponding element of "expected".
+//
+// Elements are compared as with InDelta, and the first element that is out of tolerance is
+// reported.
+func SliceInDelta(t testing.TB, expected, actual []float64, delta float64, msgAndArgs ...any) {
+	if len(expected) != len(actual) {
+		t.Helper()
+		msg := formatMsgAndArgs("Expected slices to have the same length:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nExpected: %d\nActual: %d\n", msg, len(expected), len(actual))
+		return
+	}
+	for i := range expected {
+		if inDelta(expected[i], actual[i], delta) {
+			continue
+		}
+		t.Helper()
+		msg := formatMsgAndArgs(fmt.Sprintf("Expected element %d to be within delta:", i), msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nExpected: %v\nActual: %v\nDifference: %v\nDelta: %v\n", msg, expected[i], actual[i], math.Abs(expected[i]-actual[i]), delta)
+		return
+	}
+}
+
+// WithinDuration asserts that "expected" and "actual" are within "delta" of each other.
+//
+// Monotonic clock readings are stripped before comparison.
+func WithinDuration(t testing.TB, expected, actual time.Time, delta time.Duration, msgAndArgs ...any) {
+	expected, actual = expected.Round(0), actual.Round(0)
+	diff := actual.Sub(expected)
+	if diff >= -delta && diff <= delta {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected times to be within delta:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected: %s\nActual: %s\nDifference: %s\nDelta: %s\n", msg, expected, actual, diff, delta)
+}
+
+// EqualError asserts that either an error is non-nil and that its message is what is expected,
+// or that error is nil if the expected message is empty.
+func EqualError(t testing.TB, err error, errString string, msgAndArgs ...any) {
+	if err == nil && errString == "" {
+		return
+	}
+	t.Helper()
+	if err == nil {
+		fatal(t, msgAndArgs, formatMsgAndArgs("Expected an error", msgAndArgs...))
+		return
+	}
+	if err.Error() != errString {
+		msg := formatMsgAndArgs("Error message not as expected:", msgAndArgs...)
+		diff := Diff(errString, err.Error())
+		fatalCompare(t, msgAndArgs, errString, err.Error(), diff, msg+"\n"+colorDiff(diff))
+	}
+}
+
+// ErrorContains asserts that an error is non-nil and that its message contains "substr".
+func ErrorContains(t testing.TB, err error, substr string, msgAndArgs ...any) {
+	if err != nil && strings.Contains(err.Error(), substr) {
+		return
+	}
+	t.Helper()
+	if err == nil {
+		fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Expected an error containing %q", substr), msgAndArgs...))
+		return
+	}
+	msg := formatMsgAndArgs("Error message does not contain substring.", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nSubstring: %q\nError: %q\n", msg, substr, err.Error())
+}
+
+// ErrorTreeContains asserts that an error is non-nil and that the message of it or any error
+// in its tree contains "substr".
+//
+// The tree is walked with Unwrap() error and Unwrap() []error, as with errors.Is, so a
+// message that is reformatted by a wrapping error is still found. On failure the tree that
+// was searched is displayed.
+func ErrorTreeContains(t testing.TB, err error, substr string, msgAndArgs ...any) {
+	if errorTreeContains(err, substr) {
+		return
+	}
+	t.Helper()
+	if err == nil {
+		fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Expected an error containing %q", substr), msgAndArgs...))
+		return
+	}
+	msg := formatMsgAndArgs("No error in tree contains substring.", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nSubstring: %q\n%s", msg, substr, errorTree(err, func(error) bool { return false }))
+}
+
+// NotErrorContains asserts that an error is nil or that its message does not contain "substr".
+func NotErrorContains(t testing.TB, err error, substr string, msgAndArgs ...any) {
+	if err == nil || !strings.Contains(err.Error(), substr) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Error message should not contain substring.", msgAndArgs...)
+	quotedError, quotedSubstr, positions := needlePosition(err.Error(), substr)
+	fatalf(t, msgAndArgs, "%s\nSubstring: %s\nError: %s\n       %s\n", msg, quotedSubstr, quotedError, positions)
+}
+
+// IsError asserts than any error in "err"'s tree matches "target".
+func IsError(t testing.TB, err, target error, msgAndArgs ...any) {
+	if errors.Is(err, target) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs(fmt.Sprintf("Error tree should contain error %q:", target), msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, errorTree(err, func(err error) bool { return errorMatches(err, target) }))
+}
+
+// NotIsError asserts than no error in "err"'s tree matches "target".
+func NotIsError(t testing.TB, err, target error, msgAndArgs ...any) {
+	if !errors.Is(err, target) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs(fmt.Sprintf("Error tree should NOT contain error %q:", target), msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, errorTree(err, func(err error) bool { return errorMatches(err, target) }))
+}
+
+// ErrorAs asserts that an error in "err"'s tree matches the type T, and returns it.
+func ErrorAs[T error](t testing.TB, err error, msgAndArgs ...any) T {
+	var target T
+	if errors.As(err, &target) {
+		return target
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Error tree %+v should contain error of type %s", err, typeOf[T]()), msgAndArgs...))
+	return target
+}
+
+// Error asserts that an error is not nil.
+func Error(t testing.TB, err error, msgAndArgs ...any) {
+	if err != nil {
+		return
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs("Expected an error", msgAndArgs...))
+}
+
+// NoError asserts that an error is nil.
+func NoError(t testing.TB, err error, msgAndArgs ...any) {
+	if err == nil {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Did not expect an error but got:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%+v", msg, err)
+}
+
+// Must asserts that "err" is nil and returns "value".
+//
+// Note that Go only allows a multi-valued call as the sole argument to a function, so the
+// results of a call returning (T, error) can not be passed directly to Must.
+func Must[T any](t testing.TB, value T, err error, msgAndArgs ...any) T {
+	t.Helper()
+	NoError(t, err, msgAndArgs...)
+	return value
+}
+
+// Must2 asserts that "err" is nil and returns "a" and "b".
+func Must2[A, B any](t testing.TB, a A, b B, err error, msgAndArgs ...any) (A, B) {
+	t.Helper()
+	NoError(t, err, msgAndArgs...)
+	return a, b
+}
+
+// Must3 asserts that "err" is nil and returns "a", "b" and "c".
+func Must3[A, B, C any](t testing.TB, a A, b B, c C, err error, msgAndArgs ...any) (A, B, C) {
+	t.Helper()
+	NoError(t, err, msgAndArgs...)
+	return a, b, c
+}
+
+// True asserts that an expression is true.
+func True(t testing.TB, ok bool, msgAndArgs ...any) {
+	if ok {
+		return
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs("Expected expression to be true", msgAndArgs...))
+}
+
+// That asserts that "value" satisfies "predicate", displaying the value if it does not, eg.
+//
+//	assert.That(t, response.Items, func(items []Item) bool { return len(items) > 5 })
+func That[T any](t testing.TB, value T, predicate func(T) bool, msgAndArgs ...any) {
+	if predicate(value) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Value does not satisfy predicate:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+}
+
+// Fail marks the test as failed with a message, but continues execution.
+func Fail(t testing.TB, msgAndArgs ...any) {
+	t.Helper()
+	t.Errorf("%s", reportFailure(msgAndArgs, Failure{Message: formatMsgAndArgs("Failed", msgAndArgs...)}))
+}
+
+// FailNow marks the test as failed with a message, and stops execution.
+func FailNow(t testing.TB, msgAndArgs ...any) {
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs("Failed", msgAndArgs...))
+}
+
+// NoErrorTrue asserts that "err" is nil and that "ok" is true.
+//
+// This is useful for checking the result of functions returning (bool, error).
+func NoErrorTrue(t testing.TB, ok bool, err error, msgAndArgs ...any) {
+	if err == nil && ok {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Did not expect an error but got:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%+v", msg, err)
+		return
+	}
+	fatal(t, msgAndArgs, formatMsgAndArgs("Expected expression to be true", msgAndArgs...))
+}
+
+// False asserts that an expression is false.
+func False(t testing.TB, ok bool, msgAndArgs ...any) {
+	if !ok {
+		return
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs("Expected expression to be false", msgAndArgs...))
+}
+
+// Panics asserts that the given function panics, and returns the value it panicked with.
+func Panics(t testing.TB, fn func(), msgAndArgs ...any) any {
+	if fn == nil {
+		t.Helper()
+		fatal(t, msgAndArgs, "assert.Panics called with nil function")
+		return nil
+	}
+	panicked, value := recoverPanic(fn)
+	if panicked {
+		return value
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs("Expected function to panic", msgAndArgs...))
+	return nil
+}
+
+// NotPanics asserts that the given function does not panic.
+func NotPanics(t testing.TB, fn func(), msgAndArgs ...any) {
+	if fn == nil {
+		t.Helper()
+		fatal(t, msgAndArgs, "assert.NotPanics called with nil function")
+		return
+	}
+	panicked, value := recoverPanic(fn)
+	if !panicked {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected function not to panic", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPanic: %v", msg, value)
+}
+
+// NotPanicsResult asserts that the given function does not panic, and returns its result.
+//
+// If the function panics, the stack of the panic is displayed.
+func NotPanicsResult[T any](t testing.TB, fn func() T, msgAndArgs ...any) T {
+	var result T
+	panicked, value, stack := recoverPanicStack(func() { result = fn() })
+	if !panicked {
+		return result
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Expected function not to panic", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPanic: %v\n%s", msg, value, stack)
+	return result
+}
+
+// PanicsWithValue asserts that the given function panics with a value equal to "expected".
+func PanicsWithValue(t testing.TB, expected any, fn func(), msgAndArgs ...any) {
+	t.Helper()
+	panicked, value := recoverPanic(fn)
+	if !panicked {
+		fatal(t, msgAndArgs, formatMsgAndArgs("Expected function to panic", msgAndArgs...))
+		return
+	}
+	if objectsAreEqual(expected, value) {
+		return
+	}
+	msg := formatMsgAndArgs("Expected panic value to be equal:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, colorDiff(Diff(expected, value)))
+}
+
+// PanicsWithError asserts that the given function panics with an error whose message is "errString".
+func PanicsWithError(t testing.TB, errString string, fn func(), msgAndArgs ...any) {
+	t.Helper()
+	panicked, value := recoverPanic(fn)
+	if !panicked {
+		fatal(t, msgAndArgs, formatMsgAndArgs("Expected function to panic", msgAndArgs...))
+		return
+	}
+	err, ok := value.(error)
+	if !ok {
+		msg := formatMsgAndArgs("Expected function to panic with an error but got:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+		return
+	}
+	if err.Error() == errString {
+		return
+	}
+	msg := formatMsgAndArgs("Panic error message not as expected:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\n%s", msg, colorDiff(Diff(errString, err.Error())))
+}
+
+// allocRuns is the number of times MaxAllocs calls its function.
+const allocRuns = 100
+
+// MaxAllocs asserts that "fn" makes at most "budget" heap allocations per call.
+//
+// Allocations are measured with testing.AllocsPerRun, which calls "fn" once to warm up and
+// then 100 more times, and reports the average number of allocations per call truncated to
+// an integer. "fn" should therefore allocate the same amount on every call. Note that the
+// race detector and coverage instrumentation may add allocations.
+func MaxAllocs(t testing.TB, budget int, fn func(), msgAndArgs ...any) {
+	allocs := testing.AllocsPerRun(allocRuns, fn)
+	if allocs <= float64(budget) {
+		return
+	}
+	t.Helper()
+	msg := formatMsgAndArgs("Function allocated more than its budget:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nBudget: %d\nAverage allocations per run: %v\n", msg, budget, allocs)
+}
+
+// Diff returns a unified diff of the string representation of two values.
+//
+// Only DiffContext unchanged lines are shown around each change, DefaultDiffContext by
+// default. Longer runs of unchanged lines are collapsed, and each hunk after the first is
+// introduced by a "@@ -line,count +line,count @@" header, as in git.
+//
+// Single-line strings are instead displayed one above the other, with a marker beneath the
+// first difference. If a differ has been registered for the type of the values with
+// RegisterDiffer, its output is returned instead.
+func Diff[T any](before, after T, compareOptions ...CompareOption) string {
+	if diff, ok := registeredDiff(before, after); ok {
+		return diff
+	}
+	var lhss, rhss string
+	opts := expandCompareOptions(compareOptions...)
+	lhs, rhs := opts.normalise(before), opts.normalise(after)
+	rhs = opts.align(lhs, rhs)
+	if lhsErr, rhsErr, ok := opts.errors(lhs, rhs); ok {
+		lhs, rhs = lhsErr.Error(), rhsErr.Error()
+	}
+	// Special case strings so we get nice diffs.
+	l, lok := lhs.(string)
+	r, rok := rhs.(string)
+	if lok && rok {
+		if l != r && !strings.Contains(l, "\n") && !strings.Contains(r, "\n") {
+			return inlineDiff(l, r)
+		}
+		lhss = l + "\n"
+		rhss = r + "\n"
+	} else {
+		lhss = opts.render(lhs) + "\n"
+		rhss = opts.render(rhs) + "\n"
+		if opts.numberFormat != nil {
+			lhss, rhss = opts.numberFormat.format(lhss), opts.numberFormat.format(rhss)
+		}
+	}
+	return opts.unifiedDiff(lhss, rhss)
+}
+
+// rawDiff returns a diff of the Go representation of two values, ignoring all compare options.
+func rawDiff(before, after any) string {
+	opts := &compareOptions{}
+	return opts.unifiedDiff(repr.String(before, repr.Indent("  "))+"\n", repr.String(after, repr.Indent("  "))+"\n")
+}
+
+// maxDifferences is the number of differing paths reported by DeepCompare.
+const maxDifferences = 5
+
+// differences returns a line for each path at which "expected" and "actual" differ, if
+// DeepCompare or DiffPaths is enabled and the values have any structure.
+func differences(expected, actual any, options ...CompareOption) string {
+	opts := expandCompareOptions(options...)
+	limit := opts.diffPaths
+	if limit == 0 && opts.deepCompare {
+		limit = maxDifferences
+	}
+	if limit <= 0 || !isStructured(expected) {
+		return ""
+	}
+	w := &strings.Builder{}
+	for _, path := range opts.deepDiff(opts.normalise(expected), opts.normalise(actual), limit) {
+		fmt.Fprintf(w, "Difference at %s\n", path)
+	}
+	return w.String()
+}
+
+// typeMismatch returns a line naming the types of "expected" and "actual" if they are
+// non-nil values of different dynamic types, which may otherwise render identically.
+//
+// Errors are compared by their messages, so their types are not reported unless
+// ErrorsAsValues is given.
+func typeMismatch(expected, actual any, options ...CompareOption) string {
+	opts := expandCompareOptions(options...)
+	expected, actual = opts.normalise(expected), opts.normalise(actual)
+	if expected == nil || actual == nil {
+		return ""
+	}
+	if _, _, ok := opts.errors(expected, actual); ok {
+		return ""
+	}
+	expectedType, actualType := reflect.TypeOf(expected), reflect.TypeOf(actual)
+	if expectedType == actualType {
+		return ""
+	}
+	return fmt.Sprintf("Type mismatch: %s vs %s\n", expectedType, actualType)
+}
+
+// ignorableDifferences are differences in white space, which are hard to see in diffs, along
+// with the options that ignore them.
+var ignorableDifferences = []struct {
+	option CompareOption
+	note   string
+}{
+	{IgnoreLineEndings(), "Only line endings differ, use IgnoreLineEndings to ignore them"},
+	{IgnoreTrailingSpace(), "Only trailing white space differs, use IgnoreTrailingSpace to ignore it"},
+	{TrimSpace(), "Only leading or trailing white space differs, use TrimSpace to ignore it"},
+}
+
+// ignorableDifference returns a line noting that "expected" and "actual" differ only in
+// white space, if they do.
+func ignorableDifference(expected, actual any, options ...CompareOption) string {
+	if !mayContainString(reflect.TypeOf(expected), map[reflect.Type]bool{}) || !mayContainString(reflect.TypeOf(actual), map[reflect.Type]bool{}) {
+		return ""
+	}
+	for _, difference := range ignorableDifferences {
+		if objectsAreEqual(expected, actual, append(options, difference.option)...) {
+			return difference.note + "\n"
+		}
+	}
+	return ""
+}
+
+// mayContainString returns true if a value of type "t" may hold a string at any depth.
+func mayContainString(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if t == nil || seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.String, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return mayContainString(t.Elem(), seen)
+	case reflect.Map:
+		return mayContainString(t.Key(), seen) || mayContainString(t.Elem(), seen)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if mayContainString(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isStructured returns true if value is a struct, slice, array or map, or a pointer to one.
+func isStructured(value any) bool {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	default:
+		return false
+	}
+}
+
+func inDelta(expected, actual, delta float64) bool {
+	if math.IsNaN(expected) || math.IsNaN(actual) || math.IsNaN(delta) {
+		return false
+	}
+	if math.IsInf(expected, 0) || math.IsInf(actual, 0) {
+		return expected == actual
+	}
+	return math.Abs(expected-actual) <= delta
+}
+
+func failBetween[T constraints.Ordered](t testing.TB, value, lo, hi T, rangeFormat string, msgAndArgs ...any) {
+	t.Helper()
+	if lo > hi {
+		msg := formatMsgAndArgs("Invalid range, lower bound is greater than upper bound:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nLower bound: %s\nUpper bound: %s\n", msg, repr.String(lo), repr.String(hi))
+		return
+	}
+	msg := formatMsgAndArgs("Expected value to be in range:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nValue: %s\nRange: "+rangeFormat+"\n", msg, repr.String(value), repr.String(lo), repr.String(hi))
+}
+
+// typeOf returns the reflect.Type of T, including when T is an interface type.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// as converts "value" to T, returning the zero T if "value" is nil.
+//
+// This allows nil elements of an interface type T to be passed to functions taking a T.
+func as[T any](value any) T {
+	out, _ := value.(T)
+	return out
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "nil"
+	}
+	return t.String()
+}
+
+// sign returns -1, 0 or 1 depending on the sign of n.
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func failOrdering(t testing.TB, a any, op string, b any, msgAndArgs ...any) {
+	t.Helper()
+	msg := formatMsgAndArgs("Ordering assertion failed:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nExpected %s %s %s", msg, repr.String(a), op, repr.String(b))
+}
+
+// errorTree renders the tree of errors wrapped by err, one error per line, marking those
+// for which "matches" returns true.
+func errorTree(err error, matches func(error) bool) string {
+	w := &strings.Builder{}
+	writeErrorTree(w, err, matches, "")
+	return w.String()
+}
+
+func writeErrorTree(w *strings.Builder, err error, matches func(error) bool, indent string) {
+	if err == nil {
+		fmt.Fprintf(w, "%s<nil>\n", indent)
+		return
+	}
+	fmt.Fprintf(w, "%s%T: %s", indent, err, err)
+	if matches(err) {
+		w.WriteString("  <-- matches")
+	}
+	w.WriteString("\n")
+	walkErrorTree(err, func(inner error) bool {
+		writeErrorTree(w, inner, matches, indent+"  ")
+		return false
+	})
+}
+
+// walkErrorTree calls "visit" with each error directly wrapped by "err", via Unwrap() error
+// or Unwrap() []error, and returns true as soon as "visit" does.
+func walkErrorTree(err error, visit func(inner error) bool) bool {
+	switch err := err.(type) {
+	case interface{ Unwrap() error }:
+		if inner := err.Unwrap(); inner != nil {
+			return visit(inner)
+		}
+	case interface{ Unwrap() []error }:
+		for _, inner := range err.Unwrap() {
+			if visit(inner) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// errorTreeContains reports whether the message of any error in "err"'s tree contains
+// "substr".
+func errorTreeContains(err error, substr string) bool {
+	if err == nil {
+		return false
+	}
+	if strings.Contains(err.Error(), substr) {
+		return true
+	}
+	return walkErrorTree(err, func(inner error) bool { return errorTreeContains(inner, substr) })
+}
+
+// errorMatches reports whether err itself, ignoring any errors it wraps, matches target
+// in the same way as errors.Is.
+func errorMatches(err, target error) bool {
+	if target == nil {
+		return err == target
+	}
+	if reflect.TypeOf(target).Comparable() && err == target {
+		return true
+	}
+	if is, ok := err.(interface{ Is(error) bool }); ok && is.Is(target) {
+		return true
+	}
+	return false
+}
+
+// recoverPanic calls fn and returns whether it panicked, and with what value.
+func recoverPanic(fn func()) (panicked bool, value any) {
+	panicked = true
+	defer func() {
+		if panicked {
+			value = recover()
+		}
+	}()
+	fn()
+	panicked = false
+	return
+}
+
+// recoverPanicStack is like recoverPanic, but also returns the stack of the panic.
+func recoverPanicStack(fn func()) (panicked bool, value any, stack []byte) {
+	panicked = true
+	defer func() {
+		if panicked {
+			value = recover()
+			stack = debug.Stack()
+		}
+	}()
+	fn()
+	panicked = false
+	return
 }
 
 func formatMsgAndArgs(dflt string, msgAndArgs ...any) string {
+	msgAndArgs, _ = extractDumps(msgAndArgs)
 	if len(msgAndArgs) == 0 {
 		return dflt
 	}
+	if lazy, ok := msgAndArgs[0].(func() string); ok && len(msgAndArgs) == 1 {
+		return lazy()
+	}
 	format, ok := msgAndArgs[0].(string)
 	if !ok {
-		panic("message argument to assert function must be a fmt string")
+		// Not a format string, so just include the arguments alongside the default message.
+		args := make([]string, len(msgAndArgs))
+		for i, arg := range msgAndArgs {
+			args[i] = fmt.Sprint(arg)
+		}
+		return dflt + " " + strings.Join(args, " ")
 	}
 	return fmt.Sprintf(format, msgAndArgs[1:]...)
 }
 
+// DumpValue is a named value to include in the output of a failed assertion. See Dump.
+type DumpValue struct {
+	name  string
+	value any
+}
+
+// Dump includes "value" in the output of an assertion if it fails.
+//
+// It may be passed to any assertion alongside, or instead of, a message and its arguments, eg.
+//
+//	assert.Equal(t, expected, actual, assert.Dump("request", req), assert.Dump("response", resp))
+func Dump(name string, value any) DumpValue {
+	return DumpValue{name: name, value: value}
+}
+
+func extractDumps(msgAndArgs []any) ([]any, []DumpValue) {
+	dumps := []DumpValue{}
+	out := []any{}
+	for _, arg := range msgAndArgs {
+		if dump, ok := arg.(DumpValue); ok {
+			dumps = append(dumps, dump)
+		} else {
+			out = append(out, arg)
+		}
+	}
+	return out, dumps
+}
+
+// fatal fails the test with "msg", followed by any values passed to Dump in "msgAndArgs".
+//
+// All assertions report failures through fatal or fatalCompare, so that each failure is
+// emitted by a single call and is not interleaved with output from parallel tests.
+func fatal(t testing.TB, msgAndArgs []any, msg string) {
+	t.Helper()
+	t.Fatalf("%s", reportFailure(msgAndArgs, Failure{Message: msg}))
+}
+
+// appendDumps appends any values passed to Dump in "msgAndArgs" to "msg".
+func appendDumps(msg string, msgAndArgs []any) string {
+	_, dumps := extractDumps(msgAndArgs)
+	if len(dumps) == 0 {
+		return msg
+	}
+	w := &strings.Builder{}
+	w.WriteString(strings.TrimSuffix(msg, "\n"))
+	for _, dump := range dumps {
+		fmt.Fprintf(w, "\n%s: %s", dump.name, repr.String(dump.value, repr.Indent("  ")))
+	}
+	w.WriteString("\n")
+	return w.String()
+}
+
+// fatalf is like fatal, but formats the message with fmt.Sprintf.
+func fatalf(t testing.TB, msgAndArgs []any, format string, args ...any) {
+	t.Helper()
+	fatal(t, msgAndArgs, fmt.Sprintf(format, args...))
+}
+
+// lengthOf returns the length of a slice, array, map, string or channel, and false for any other kind.
+func lengthOf(value any) (int, bool) {
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map, reflect.String, reflect.Chan:
+		return v.Len(), true
+	default:
+		return 0, false
+	}
+}
+
+// diffElements returns the elements of expected that are not in actual, and the elements
+// of actual that are not in expected, matching each element at most once.
+func diffElements[T any](expected, actual []T) (missing, extra []T) {
+	mapping, used := matchElements(expected, actual)
+	return unmatchedElements(expected, actual, mapping, used)
+}
+
+// unmatchedElements returns the elements of expected and actual that were not matched by
+// matchElements.
+func unmatchedElements[T any](expected, actual []T, mapping []int, used []bool) (missing, extra []T) {
+	missing, extra = []T{}, []T{}
+	for i, e := range expected {
+		if mapping[i] == -1 {
+			missing = append(missing, e)
+		}
+	}
+	for i, a := range actual {
+		if !used[i] {
+			extra = append(extra, a)
+		}
+	}
+	return missing, extra
+}
+
+// matchElements matches each element of expected with the first unused equal element of
+// actual, returning the index in actual of each element of expected, or -1 if there is no
+// match, and which elements of actual were matched.
+func matchElements[T any](expected, actual []T) (mapping []int, used []bool) {
+	mapping = make([]int, len(expected))
+	used = make([]bool, len(actual))
+next:
+	for i, e := range expected {
+		for j, a := range actual {
+			if !used[j] && objectsAreEqual(e, a) {
+				used[j] = true
+				mapping[i] = j
+				continue next
+			}
+		}
+		mapping[i] = -1
+	}
+	return mapping, used
+}
+
+// diffMapKeys returns the keys only in "expected", the keys only in "actual", and the keys
+// whose values differ, each sorted by their representation.
+func diffMapKeys[K comparable, V any](expected, actual map[K]V, options ...CompareOption) (missing, extra, changed []K) {
+	for k, ev := range expected {
+		av, ok := actual[k]
+		if !ok {
+			missing = append(missing, k)
+		} else if !objectsAreEqual(ev, av, options...) {
+			changed = append(changed, k)
+		}
+	}
+	for k := range actual {
+		if _, ok := expected[k]; !ok {
+			extra = append(extra, k)
+		}
+	}
+	for _, keys := range [][]K{missing, extra, changed} {
+		sort.Slice(keys, func(i, j int) bool { return repr.String(keys[i]) < repr.String(keys[j]) })
+	}
+	return missing, extra, changed
+}
+
+// indent all lines of s after the first with "prefix".
+func indent(s, prefix string) string {
+	return strings.ReplaceAll(s, "\n", "\n"+prefix)
+}
+
+// missingElements returns the elements of "elements" that are not in "list".
+func missingElements[T any](list, elements []T) []T {
+	missing := []T{}
+next:
+	for _, e := range elements {
+		for _, item := range list {
+			if objectsAreEqual(item, e) {
+				continue next
+			}
+		}
+		missing = append(missing, e)
+	}
+	return missing
+}
+
+func isEmpty(value any) bool {
+	if isNil(value) {
+		return true
+	}
+	length, ok := lengthOf(value)
+	return ok && length == 0
+}
+
+func isNil(value any) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Ptr, reflect.Slice, reflect.UnsafePointer:
+		return v.IsNil()
+	default:
+		return false
+	}
+}
+
+// nilRepr returns a representation of a nil value that includes its dynamic type, eg. "(*bytes.Buffer)(nil)".
+func nilRepr(value any) string {
+	if value == nil {
+		return "nil"
+	}
+	return fmt.Sprintf("(%T)(nil)", value)
+}
+
+// needlePosition returns the quoted forms of haystack and needle, and a line of carets
+// aligned beneath every occurrence of needle in the quoted haystack, including overlapping
+// occurrences.
 func needlePosition(haystack, needle string) (quotedHaystack, quotedNeedle, positions string) {
 	quotedNeedle = strconv.Quote(needle)
 	quotedNeedle = quotedNeedle[1 : len(quotedNeedle)-1]
-	quotedHaystack = strconv.Quote(haystack)
-	rawPositions := strings.ReplaceAll(quotedHaystack, quotedNeedle, strings.Repeat("^", len(quotedNeedle)))
-	for _, rn := range rawPositions {
-		if rn != '^' {
-			positions += " "
-		} else {
-			positions += "^"
+	marked := make([]bool, len(haystack))
+	for i := 0; needle != "" && i < len(haystack); {
+		index := strings.Index(haystack[i:], needle)
+		if index == -1 {
+			break
+		}
+		start := i + index
+		for j := start; j < start+len(needle); j++ {
+			marked[j] = true
 		}
+		_, size := utf8.DecodeRuneInString(haystack[start:])
+		i = start + size
 	}
+	quotedHaystack, positions = markPositions(haystack, marked)
 	return
 }
 
-func expandCompareOptions(options ...CompareOption) []repr.Option {
-	ropts := []repr.Option{repr.Indent("  ")}
+// markPositions returns the quoted form of s and a line of carets aligned beneath each rune
+// of s containing a byte for which marked is true.
+func markPositions(s string, marked []bool) (quoted, positions string) {
+	q := &strings.Builder{}
+	p := &strings.Builder{}
+	q.WriteByte('"')
+	p.WriteByte(' ')
+	for i := 0; i < len(s); {
+		_, size := utf8.DecodeRuneInString(s[i:])
+		piece := strconv.Quote(s[i : i+size])
+		piece = piece[1 : len(piece)-1]
+		q.WriteString(piece)
+		mark := " "
+		for j := i; j < i+size; j++ {
+			if marked[j] {
+				mark = "^"
+				break
+			}
+		}
+		p.WriteString(strings.Repeat(mark, utf8.RuneCountInString(piece)))
+		i += size
+	}
+	q.WriteByte('"')
+	return q.String(), strings.TrimRight(p.String(), " ")
+}
+
+const hexDumpWidth = 16
+
+// hexDump returns a hex dump of data[start:end] in the same format as hex.Dump, but with
+// offsets relative to the start of data.
+//
+// Rows overlapping data[markStart:markEnd] are followed by a line of carets beneath the
+// marked bytes.
+func hexDump(data []byte, start, end, markStart, markEnd int) string {
+	return hexDumpAt(data, 0, start, end, markStart, markEnd)
+}
+
+// hexDumpAt is like hexDump, but offsets are displayed relative to "base" bytes before the
+// start of data. "base" must be a multiple of hexDumpWidth.
+func hexDumpAt(data []byte, base int64, start, end, markStart, markEnd int) string {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(data) {
+		end = len(data)
+	}
+	w := &strings.Builder{}
+	for row := start - start%hexDumpWidth; row < end; row += hexDumpWidth {
+		hexes := &strings.Builder{}
+		marks := &strings.Builder{}
+		ascii := &strings.Builder{}
+		marked := false
+		for i := row; i < row+hexDumpWidth; i++ {
+			if i == row+hexDumpWidth/2 {
+				hexes.WriteByte(' ')
+				marks.WriteByte(' ')
+			}
+			if i < start || i >= end {
+				hexes.WriteString("   ")
+				marks.WriteString("   ")
+				continue
+			}
+			fmt.Fprintf(hexes, "%02x ", data[i])
+			if i >= markStart && i < markEnd {
+				marks.WriteString("^^ ")
+				marked = true
+			} else {
+				marks.WriteString("   ")
+			}
+			if data[i] < 32 || data[i] > 126 {
+				ascii.WriteByte('.')
+			} else {
+				ascii.WriteByte(data[i])
+			}
+		}
+		fmt.Fprintf(w, "%08x  %s |%s|\n", base+int64(row), hexes, ascii)
+		if marked {
+			fmt.Fprintf(w, "          %s\n", strings.TrimRight(marks.String(), " "))
+		}
+	}
+	return w.String()
+}
+
+// hasPrefixFold is a Unicode case-folding equivalent of strings.HasPrefix.
+//
+// Runes are compared individually, as a rune may have a different encoded length than the
+// rune it folds to.
+func hasPrefixFold(s, prefix string) bool {
+	for prefix != "" {
+		if s == "" {
+			return false
+		}
+		pr, pn := utf8.DecodeRuneInString(prefix)
+		sr, sn := utf8.DecodeRuneInString(s)
+		if !strings.EqualFold(string(pr), string(sr)) {
+			return false
+		}
+		prefix, s = prefix[pn:], s[sn:]
+	}
+	return true
+}
+
+// hasSuffixFold is a Unicode case-folding equivalent of strings.HasSuffix.
+func hasSuffixFold(s, suffix string) bool {
+	for suffix != "" {
+		if s == "" {
+			return false
+		}
+		sfr, sfn := utf8.DecodeLastRuneInString(suffix)
+		sr, sn := utf8.DecodeLastRuneInString(s)
+		if !strings.EqualFold(string(sfr), string(sr)) {
+			return false
+		}
+		suffix, s = suffix[:len(suffix)-sfn], s[:len(s)-sn]
+	}
+	return true
+}
+
+// matchPosition returns the quoted form of s and a line of carets aligned beneath the quoted s[start:end].
+func matchPosition(s string, start, end int) (quoted, positions string) {
+	marked := make([]bool, len(s))
+	for i := start; i < end; i++ {
+		marked[i] = true
+	}
+	return markPositions(s, marked)
+}
+
+func compilePattern[P Pattern](pattern P) (*regexp.Regexp, error) {
+	switch pattern := any(pattern).(type) {
+	case *regexp.Regexp:
+		if pattern == nil {
+			return nil, errors.New("nil pattern")
+		}
+		return pattern, nil
+	case string:
+		return regexp.Compile(pattern)
+	default:
+		panic("unreachable")
+	}
+}
+
+type compareOptions struct {
+	reprOptions      []repr.Option
+	normalisers      []normaliser
+	comparators      []comparator
+	exclude          map[reflect.Type]bool
+	omitEmpty        bool
+	ignoreGoStringer bool
+	ignoreUnexported bool
+	diffContext      int
+	diffContextSet   bool
+	numberFormat     *numberFormat
+	deepCompare      bool
+	diffPaths        int
+	errorsAsValues   bool
+	maxDiffLines     int
+	maxDiffLinesSet  bool
+	firstDiffOnly    bool
+	applied          []string // Descriptions of the options that may cause values to compare equal.
+}
+
+// errors returns "expected" and "actual" as errors if both are non-nil errors and errors are
+// not being compared as values.
+func (o *compareOptions) errors(expected, actual any) (expectedErr, actualErr error, ok bool) {
+	if o.errorsAsValues || isNil(expected) || isNil(actual) {
+		return nil, nil, false
+	}
+	expectedErr, eok := expected.(error)
+	actualErr, aok := actual.(error)
+	return expectedErr, actualErr, eok && aok
+}
+
+// render a value with repr, honouring the comparison options.
+func (o *compareOptions) render(value any) string {
+	if s, ok := bigString(value); ok {
+		return s
+	}
+	return repr.String(value, o.reprOptions...)
+}
+
+func expandCompareOptions(options ...CompareOption) *compareOptions {
+	opts := &compareOptions{
+		reprOptions: []repr.Option{repr.Indent("  ")},
+		exclude:     map[reflect.Type]bool{},
+		omitEmpty:   true, // Matches the repr default.
+	}
+	defaultCompareOptionsLock.RLock()
+	defaults := defaultCompareOptions
+	defaultCompareOptionsLock.RUnlock()
+	for _, option := range defaults {
+		option(opts)
+	}
 	for _, option := range options {
-		ropts = append(ropts, option()...)
+		option(opts)
 	}
-	return ropts
+	return opts
 }
 
 func objectsAreEqual(expected, actual any, options ...CompareOption) bool {
+	opts := expandCompareOptions(options...)
+	expected, actual = opts.normalise(expected), opts.normalise(actual)
+	actual = opts.align(expected, actual)
+	if expectedErr, actualErr, ok := opts.errors(expected, actual); ok {
+		return errors.Is(actualErr, expectedErr) || actualErr.Error() == expectedErr.Error()
+	}
+	if opts.deepCompare {
+		return len(opts.deepDiff(expected, actual, 1)) == 0
+	}
 	if expected == nil || actual == nil {
 		return expected == actual
 	}
@@ -345,9 +2296,44 @@ func objectsAreEqual(expected, actual any, options ...CompareOption) bool {
 		}
 	}
 
-	ropts := expandCompareOptions(options...)
-	expectedStr := repr.String(expected, ropts...)
-	actualStr := repr.String(actual, ropts...)
+	if equal, ok := bigEqual(expected, actual); ok {
+		return equal
+	}
+
+	if opts.render(expected) == opts.render(actual) {
+		return true
+	}
+	// repr sorts map keys by their fmt.Sprint representation, so keys that print identically,
+	// such as 1 and "1" in a map[any]T, may be rendered in a different order for equal maps.
+	// Equal math/big numbers may also have different internal representations. Compare such
+	// values field by field instead.
+	if mayRenderDifferently(reflect.TypeOf(expected), map[reflect.Type]bool{}) {
+		return len(opts.deepDiff(expected, actual, 1)) == 0
+	}
+	return false
+}
 
-	return expectedStr == actualStr
+// mayRenderDifferently returns true if equal values of type t may be rendered differently,
+// because they may contain a map or a math/big number.
+func mayRenderDifferently(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Map, reflect.Interface:
+		return true
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return mayRenderDifferently(t.Elem(), seen)
+	case reflect.Struct:
+		if isBigType(t) {
+			return true
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if mayRenderDifferently(t.Field(i).Type, seen) {
+				return true
+			}
+		}
+	}
+	return false
 }
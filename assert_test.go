@@ -1,9 +1,23 @@
 package assert
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"math/big"
 	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
 	"testing"
+	"time"
+
+	"github.com/alecthomas/repr"
 )
 
 type Data struct {
@@ -11,6 +25,12 @@ type Data struct {
 	Num int64
 }
 
+func TestMain(m *testing.M) {
+	// Tests compare exact diff output, so never color it.
+	SetColor(false)
+	os.Exit(m.Run())
+}
+
 func TestEqual(t *testing.T) {
 	assertOk(t, "IdenticalStruct", func(t testing.TB) {
 		Equal(t, Data{"expected", 1234}, Data{"expected", 1234})
@@ -36,6 +56,383 @@ func TestEqual(t *testing.T) {
 	assertOk(t, "Exclude", func(t testing.TB) {
 		Equal(t, Data{Str: "expected", Num: 1234}, Data{Str: "expected"}, Exclude[int64]())
 	})
+	assertOk(t, "ReprOptions", func(t testing.TB) {
+		Equal(t, Data{Str: "expected", Num: 1234}, Data{Str: "expected"}, ReprOptions(repr.Hide[int64]()))
+	})
+}
+
+type Model struct {
+	ID        int
+	Name      string
+	CreatedAt time.Time
+	Children  []*Model
+}
+
+type codeError struct {
+	Code int
+}
+
+func (c *codeError) Error() string { return "failed" }
+
+func TestEqualErrors(t *testing.T) {
+	sentinel := errors.New("not found")
+	assertOk(t, "SameMessage", func(t testing.TB) {
+		Equal(t, errors.New("not found"), errors.New("not found"))
+	})
+	assertOk(t, "Wrapped", func(t testing.TB) {
+		Equal(t, sentinel, fmt.Errorf("loading config: %w", sentinel))
+	})
+	assertFail(t, "DifferentMessage", func(t testing.TB) {
+		Equal(t, errors.New("not found"), errors.New("permission denied"))
+	})
+	assertFail(t, "NotWrapped", func(t testing.TB) {
+		Equal(t, fmt.Errorf("loading config: %w", sentinel), sentinel)
+	})
+	assertOk(t, "Nil", func(t testing.TB) {
+		Equal[error](t, nil, nil)
+	})
+	assertFail(t, "OneNil", func(t testing.TB) {
+		Equal(t, sentinel, nil)
+	})
+	assertOk(t, "FieldsIgnored", func(t testing.TB) {
+		Equal[error](t, &codeError{Code: 1}, &codeError{Code: 2})
+	})
+	assertFail(t, "ErrorsAsValues", func(t testing.TB) {
+		Equal[error](t, &codeError{Code: 1}, &codeError{Code: 2}, ErrorsAsValues())
+	})
+	tester := &testTester{T: t}
+	Equal(tester, errors.New("not found"), errors.New("not fond"))
+	Equal(t, "Expected values to be equal:\nExpected: \"not found\"\nActual:   \"not fond\"\n                 ^\n", tester.failed)
+}
+
+func TestEqualTypeMismatch(t *testing.T) {
+	tester := &testTester{T: t}
+	Equal[any](tester, Data{Str: "a"}, &Data{Str: "a"})
+	HasPrefix(t, tester.failed, "Expected values to be equal:\nType mismatch: assert.Data vs *assert.Data\n")
+	SliceContains[any](tester, []any{int32(1), &Data{Str: "a"}}, Data{Str: "a"})
+	Contains(t, tester.failed, "Closest element [1]:\nType mismatch: assert.Data vs *assert.Data\n")
+	Equal[any](tester, "a", "b")
+	NotContains(t, tester.failed, "Type mismatch")
+	Equal[error](tester, errors.New("not found"), &codeError{})
+	NotContains(t, tester.failed, "Type mismatch")
+}
+
+func TestEqualMapOrderIsStable(t *testing.T) {
+	type Config struct {
+		Labels   map[string]string
+		Settings map[any]int
+	}
+	build := func() Config {
+		config := Config{Labels: map[string]string{}, Settings: map[any]int{}}
+		for i := 0; i < 100; i++ {
+			config.Labels[fmt.Sprintf("key%d", i)] = strconv.Itoa(i)
+		}
+		// These keys all render as "1" with fmt.Sprint, which repr uses to sort them.
+		config.Settings[1] = 1
+		config.Settings["1"] = 2
+		config.Settings[int8(1)] = 3
+		config.Settings[uint(1)] = 4
+		return config
+	}
+	for i := 0; i < 100; i++ {
+		Equal(t, build(), build())
+	}
+	different := build()
+	different.Settings["1"] = 5
+	assertFail(t, "Different", func(t testing.TB) {
+		Equal(t, build(), different)
+	})
+}
+
+func TestExcludeFields(t *testing.T) {
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, Model{ID: 1, CreatedAt: time.Unix(1, 0)}, Model{ID: 1, CreatedAt: time.Unix(2, 0)}, ExcludeFields("CreatedAt"))
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		expected := &Model{ID: 1, Children: []*Model{{ID: 2, CreatedAt: time.Unix(1, 0)}}}
+		actual := &Model{ID: 1, Children: []*Model{{ID: 2, CreatedAt: time.Unix(2, 0)}}}
+		Equal(t, expected, actual, ExcludeFields("CreatedAt"))
+	})
+	assertOk(t, "Map", func(t testing.TB) {
+		expected := map[string]Model{"a": {ID: 1, Name: "a"}}
+		actual := map[string]Model{"a": {ID: 1, Name: "b"}}
+		Equal(t, expected, actual, ExcludeFields("Name"))
+	})
+	assertFail(t, "OtherFieldsDiffer", func(t testing.TB) {
+		Equal(t, Model{ID: 1, CreatedAt: time.Unix(1, 0)}, Model{ID: 2, CreatedAt: time.Unix(2, 0)}, ExcludeFields("CreatedAt"))
+	})
+}
+
+func TestIgnoreMapKeys(t *testing.T) {
+	type Key string
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, map[string]any{"name": "Alice", "request_id": "a"}, map[string]any{"name": "Alice", "request_id": "b"}, IgnoreMapKeys("request_id"))
+	})
+	assertOk(t, "Missing", func(t testing.TB) {
+		Equal(t, map[string]int{"a": 1}, map[string]int{"a": 1, "timestamp": 2}, IgnoreMapKeys("request_id", "timestamp"))
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		type response struct{ Body map[Key][]map[string]any }
+		expected := response{Body: map[Key][]map[string]any{"users": {{"id": 1, "timestamp": 1}}}}
+		actual := response{Body: map[Key][]map[string]any{"users": {{"id": 1, "timestamp": 2}}}}
+		Equal(t, expected, actual, IgnoreMapKeys("timestamp"))
+	})
+	assertFail(t, "OtherKeysDiffer", func(t testing.TB) {
+		Equal(t, map[string]int{"a": 1, "timestamp": 1}, map[string]int{"a": 2, "timestamp": 2}, IgnoreMapKeys("timestamp"))
+	})
+	assertFail(t, "NonStringKeys", func(t testing.TB) {
+		Equal(t, map[int]int{1: 1}, map[int]int{1: 2}, IgnoreMapKeys("1"))
+	})
+}
+
+func TestIgnoreCase(t *testing.T) {
+	type Name string
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, "Hello", "hELLO", IgnoreCase())
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		expected := &Model{Name: "Alice", Children: []*Model{{Name: "BOB"}}}
+		actual := &Model{Name: "alice", Children: []*Model{{Name: "bob"}}}
+		Equal(t, expected, actual, IgnoreCase())
+	})
+	assertOk(t, "SlicesAndMaps", func(t testing.TB) {
+		Equal(t, map[string][]Name{"Key": {"A", "b"}}, map[string][]Name{"Key": {"a", "B"}}, IgnoreCase())
+	})
+	assertFail(t, "MapKeys", func(t testing.TB) {
+		Equal(t, map[string]int{"Key": 1}, map[string]int{"KEY": 1}, IgnoreCase())
+	})
+	assertFail(t, "MapKeysDifferingInCase", func(t testing.TB) {
+		Equal(t, map[string]int{"A": 1, "a": 2}, map[string]int{"a": 2}, IgnoreCase())
+	})
+	assertFail(t, "Default", func(t testing.TB) {
+		Equal(t, "Hello", "hello")
+	})
+	assertFail(t, "DifferentStrings", func(t testing.TB) {
+		Equal(t, &Model{Name: "Alice"}, &Model{Name: "Bob"}, IgnoreCase())
+	})
+	assertFail(t, "OtherFieldsDiffer", func(t testing.TB) {
+		Equal(t, &Model{ID: 1, Name: "Alice"}, &Model{ID: 2, Name: "alice"}, IgnoreCase())
+	})
+}
+
+type unexported struct {
+	name string
+	when time.Time
+}
+
+func TestIgnoreLineEndings(t *testing.T) {
+	assertOk(t, "String", func(t testing.TB) {
+		Equal(t, "a\nb\nc\n", "a\r\nb\rc\r\n", IgnoreLineEndings())
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, map[string][]Data{"a": {{Str: "b\nc"}}}, map[string][]Data{"a": {{Str: "b\r\nc"}}}, IgnoreLineEndings())
+	})
+	assertFail(t, "MapKeys", func(t testing.TB) {
+		Equal(t, map[string]int{"a\n": 1, "a\r\n": 1}, map[string]int{"a\n": 1}, IgnoreLineEndings())
+	})
+	assertFail(t, "DifferentText", func(t testing.TB) {
+		Equal(t, "a\nb\n", "a\r\nc\r\n", IgnoreLineEndings())
+	})
+	tester := &testTester{T: t}
+	Equal(tester, Data{Str: "a\nb\n"}, Data{Str: "a\r\nb\r\n"})
+	HasPrefix(t, tester.failed, "Expected values to be equal:\nOnly line endings differ, use IgnoreLineEndings to ignore them\n")
+	Equal(tester, "a\nb\n", "a\r\nc\r\n")
+	NotContains(t, tester.failed, "line endings")
+}
+
+func TestIgnorableDifferenceAliasedPointers(t *testing.T) {
+	type inner struct{ Name string }
+	type outer struct{ In inner }
+	type holder struct {
+		Outer *outer
+		In    *inner
+	}
+	o1, o2 := &outer{inner{"a"}}, &outer{inner{"b"}}
+	tester := &testTester{T: t}
+	Equal(tester, holder{o1, &o1.In}, holder{o2, &o2.In})
+	HasPrefix(t, tester.failed, "Expected values to be equal:\n assert.holder{\n")
+	False(t, mayContainString(reflect.TypeOf(map[int][]*float64{}), map[reflect.Type]bool{}))
+	True(t, mayContainString(reflect.TypeOf(holder{}), map[reflect.Type]bool{}))
+}
+
+func TestIgnoreTrailingSpace(t *testing.T) {
+	assertOk(t, "String", func(t testing.TB) {
+		Equal(t, "a\nb\n", "a  \nb\t\n", IgnoreTrailingSpace())
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, []Data{{Str: "a\nb"}}, []Data{{Str: "a \nb "}}, IgnoreTrailingSpace())
+	})
+	assertFail(t, "LeadingSpace", func(t testing.TB) {
+		Equal(t, "a\nb\n", "a\n  b\n", IgnoreTrailingSpace())
+	})
+	assertFail(t, "TrailingNewline", func(t testing.TB) {
+		Equal(t, "a\nb", "a\nb\n", IgnoreTrailingSpace())
+	})
+	tester := &testTester{T: t}
+	Equal(tester, "a\nb\n", "a  \nb\n")
+	HasPrefix(t, tester.failed, "Expected values to be equal:\nOnly trailing white space differs, use IgnoreTrailingSpace to ignore it\n")
+}
+
+func TestTrimSpace(t *testing.T) {
+	assertOk(t, "String", func(t testing.TB) {
+		Equal(t, "a\nb", "\n a\nb\n\n", TrimSpace())
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, map[string]Data{"a": {Str: "b"}}, map[string]Data{"a": {Str: "b\n"}}, TrimSpace())
+	})
+	assertFail(t, "MapKeys", func(t testing.TB) {
+		Equal(t, map[string]int{"a ": 1, "a": 1}, map[string]int{"a": 1}, TrimSpace())
+	})
+	assertFail(t, "InnerSpace", func(t testing.TB) {
+		Equal(t, "a b", "a  b", TrimSpace())
+	})
+	tester := &testTester{T: t}
+	Equal(tester, Data{Str: "a\nb"}, Data{Str: "a\nb\n"})
+	HasPrefix(t, tester.failed, "Expected values to be equal:\nOnly leading or trailing white space differs, use TrimSpace to ignore it\n")
+	Equal(tester, "a b", "a  b")
+	NotContains(t, tester.failed, "white space")
+}
+
+type guarded struct {
+	sync.Mutex
+	Name  string
+	count int
+}
+
+func TestIgnoreUnexported(t *testing.T) {
+	locked := &guarded{Name: "a", count: 1}
+	locked.Lock()
+	defer locked.Unlock()
+	unlocked := &guarded{Name: "a", count: 2}
+	assertFail(t, "Default", func(t testing.TB) {
+		Equal(t, unlocked, locked)
+	})
+	assertOk(t, "Mutex", func(t testing.TB) {
+		Equal(t, unlocked, locked, IgnoreUnexported())
+	})
+	assertOk(t, "DeepCompare", func(t testing.TB) {
+		Equal(t, unlocked, locked, IgnoreUnexported(), DeepCompare())
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, map[string][]*guarded{"a": {unlocked}}, map[string][]*guarded{"a": {locked}}, IgnoreUnexported())
+	})
+	assertFail(t, "Exported", func(t testing.TB) {
+		Equal(t, &guarded{Name: "a"}, &guarded{Name: "b"}, IgnoreUnexported())
+	})
+	assertFail(t, "Time", func(t testing.TB) {
+		Equal(t, time.Unix(1, 0), time.Unix(2, 0), IgnoreUnexported())
+	})
+	tester := &testTester{T: t}
+	Equal(tester, &guarded{Name: "a", count: 1}, &guarded{Name: "b", count: 2}, IgnoreUnexported())
+	Equal(t, "Expected values to be equal:\n &assert.guarded{\n-  Name: \"a\",\n+  Name: \"b\",\n }\n", tester.failed)
+}
+
+func TestUnexportedFieldsCompared(t *testing.T) {
+	assertFail(t, "DifferentFields", func(t testing.TB) {
+		Equal(t, unexported{name: "a"}, unexported{name: "b"})
+	})
+	assertFail(t, "Pointers", func(t testing.TB) {
+		Equal(t, &unexported{name: "a"}, &unexported{name: "b"})
+	})
+	assertOk(t, "SameFields", func(t testing.TB) {
+		Equal(t, unexported{name: "a"}, unexported{name: "a"})
+	})
+	now := time.Now()
+	assertFail(t, "DifferentTimes", func(t testing.TB) {
+		Equal(t, unexported{when: now}, unexported{when: now.Add(time.Second)})
+	})
+	Contains(t, Diff(unexported{name: "a"}, unexported{name: "b"}), `-  name: "a",`)
+}
+
+func TestSetDefaultCompareOptions(t *testing.T) {
+	restore := SetDefaultCompareOptions(Exclude[int64]())
+	assertOk(t, "Equal", func(t testing.TB) {
+		Equal(t, Data{Str: "expected", Num: 1234}, Data{Str: "expected"})
+	})
+	assertFail(t, "NotEqual", func(t testing.TB) {
+		NotEqual(t, Data{Str: "expected", Num: 1234}, Data{Str: "expected"})
+	})
+	assertOk(t, "SliceContains", func(t testing.TB) {
+		SliceContains(t, []Data{{Str: "expected"}}, Data{Str: "expected", Num: 1234})
+	})
+	Equal(t, "", Diff(Data{Str: "expected", Num: 1234}, Data{Str: "expected"}))
+	assertOk(t, "PerCallOptionsApplied", func(t testing.TB) {
+		Equal(t, Data{Str: "EXPECTED", Num: 1234}, Data{Str: "expected"}, IgnoreCase())
+	})
+	restore()
+	assertFail(t, "Restored", func(t testing.TB) {
+		Equal(t, Data{Str: "expected", Num: 1234}, Data{Str: "expected"})
+	})
+
+	defer SetDefaultCompareOptions(DiffContext(0))()
+	before := numberedLines(20, map[int]string{10: "before"})
+	after := numberedLines(20, map[int]string{10: "after"})
+	Equal(t, "-before\n+after\n", Diff(before, after))
+	Equal(t, " 9\n-before\n+after\n 11\n", Diff(before, after, DiffContext(1)))
+}
+
+type version struct {
+	major, minor int
+	build        string
+}
+
+func (v *version) String() string { return fmt.Sprintf("%d.%d", v.major, v.minor) }
+
+type release struct {
+	Name    string
+	Version *version
+}
+
+func TestCompareStringer(t *testing.T) {
+	assertOk(t, "SameString", func(t testing.TB) {
+		Equal(t, &version{1, 2, "a"}, &version{1, 2, "b"}, CompareStringer())
+	})
+	assertFail(t, "DifferentString", func(t testing.TB) {
+		Equal(t, &version{1, 2, "a"}, &version{1, 3, "a"}, CompareStringer())
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, release{"x", &version{1, 2, "a"}}, release{"x", &version{1, 2, "b"}}, CompareStringer())
+	})
+	assertFail(t, "NestedWithoutOption", func(t testing.TB) {
+		Equal(t, release{"x", &version{1, 2, "a"}}, release{"x", &version{1, 2, "b"}})
+	})
+	assertFail(t, "NestedNil", func(t testing.TB) {
+		Equal(t, release{"x", &version{1, 2, "a"}}, release{"x", nil}, CompareStringer())
+	})
+	assertOk(t, "BothNil", func(t testing.TB) {
+		Equal(t, release{"x", nil}, release{"x", nil}, CompareStringer())
+	})
+}
+
+func TestSortSlices(t *testing.T) {
+	byID := SortSlices(func(a, b *Model) bool { return a.ID < b.ID })
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, []int{1, 2, 3}, []int{3, 1, 2}, SortSlices(func(a, b int) bool { return a < b }))
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		expected := &Model{Children: []*Model{{ID: 1}, {ID: 2, Children: []*Model{{ID: 3}, {ID: 4}}}}}
+		actual := &Model{Children: []*Model{{ID: 2, Children: []*Model{{ID: 4}, {ID: 3}}}, {ID: 1}}}
+		Equal(t, expected, actual, byID)
+	})
+	assertFail(t, "DifferentElements", func(t testing.TB) {
+		Equal(t, []int{1, 2, 3}, []int{3, 1, 1}, SortSlices(func(a, b int) bool { return a < b }))
+	})
+	assertFail(t, "OtherElementType", func(t testing.TB) {
+		Equal(t, []string{"a", "b"}, []string{"b", "a"}, SortSlices(func(a, b int) bool { return a < b }))
+	})
+	assertOk(t, "NilInterfaceElements", func(t testing.TB) {
+		message := func(err error) string {
+			if err == nil {
+				return ""
+			}
+			return err.Error()
+		}
+		byMessage := SortSlices(func(a, b error) bool { return message(a) < message(b) })
+		Equal(t, []error{nil, os.ErrClosed, nil}, []error{os.ErrClosed, nil, nil}, byMessage, ErrorsAsValues())
+	})
+	original := []int{3, 1, 2}
+	Equal(t, []int{1, 2, 3}, original, SortSlices(func(a, b int) bool { return a < b }))
+	Equal(t, []int{3, 1, 2}, original)
 }
 
 func TestEqualStrings(t *testing.T) {
@@ -56,6 +453,94 @@ func TestNotEqual(t *testing.T) {
 	})
 }
 
+type invoice struct {
+	Total *big.Rat
+	Items []*big.Int
+	Rate  big.Float
+}
+
+func TestEqualBig(t *testing.T) {
+	assertOk(t, "Int", func(t testing.TB) {
+		Equal(t, big.NewInt(0), new(big.Int).Sub(big.NewInt(1<<62), big.NewInt(1<<62)))
+	})
+	assertOk(t, "Rat", func(t testing.TB) {
+		Equal(t, new(big.Rat).SetInt64(2), new(big.Rat).SetFrac64(4, 2))
+	})
+	assertOk(t, "Float", func(t testing.TB) {
+		Equal(t, big.NewFloat(1.5), new(big.Float).SetPrec(200).SetFloat64(1.5))
+	})
+	assertFail(t, "Different", func(t testing.TB) {
+		Equal(t, big.NewInt(1), big.NewInt(2))
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		Equal(t, big.NewInt(0), nil)
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t,
+			invoice{Total: new(big.Rat).SetInt64(2), Items: []*big.Int{big.NewInt(0)}, Rate: *big.NewFloat(0.5)},
+			invoice{Total: new(big.Rat).SetFrac64(4, 2), Items: []*big.Int{new(big.Int).Sub(big.NewInt(1<<62), big.NewInt(1<<62))}, Rate: *new(big.Float).SetPrec(100).SetFloat64(0.5)})
+	})
+	assertFail(t, "NestedDifferent", func(t testing.TB) {
+		Equal(t, invoice{Total: big.NewRat(1, 3)}, invoice{Total: big.NewRat(1, 4)})
+	})
+	assertOk(t, "DeepCompare", func(t testing.TB) {
+		Equal(t, invoice{Total: new(big.Rat).SetInt64(2)}, invoice{Total: new(big.Rat).SetFrac64(4, 2)}, DeepCompare())
+	})
+	Equal(t, "-1/3\n+1/4\n", Diff(big.NewRat(1, 3), big.NewRat(1, 4)))
+	Equal(t, "-0.1\n+0.2\n", Diff(big.NewFloat(0.1), big.NewFloat(0.2)))
+}
+
+func TestNotEqualMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	NotEqual(tester, Data{Str: "expected", Num: 1234}, Data{Str: "expected", Num: 1234})
+	Equal(t, "Expected values to not be equal but both were:\nassert.Data{\n  Str: \"expected\",\n  Num: 1234,\n}", tester.failed)
+
+	NotEqual(tester, Data{Str: "expected", Num: 1234}, Data{Str: "Expected"}, Exclude[int64](), IgnoreCase(), DiffContext(0))
+	Equal(t, `Expected values to not be equal but both were:
+assert.Data{
+  Str: "expected",
+  Num: 1234,
+}
+Compare options applied:
+  Exclude[int64]() ignores fields of type int64
+  IgnoreCase() ignores the case of strings
+Differences ignored:
+ assert.Data{
+-  Str: "expected",
+-  Num: 1234,
++  Str: "Expected",
+ }
+`, tester.failed)
+
+	restore := SetDefaultCompareOptions(ExcludeFields("Num", "Str"))
+	defer restore()
+	NotEqual(tester, Data{Str: "a", Num: 1}, Data{Str: "a", Num: 1}, CompareStringer())
+	HasSuffix(t, tester.failed, "\nCompare options applied:\n  ExcludeFields(\"Num\", \"Str\") ignores fields with these names\n  CompareStringer() compares fmt.Stringer values by their String methods\n")
+}
+
+func TestSame(t *testing.T) {
+	data := &Data{"a", 1}
+	assertOk(t, "SamePointer", func(t testing.TB) {
+		Same(t, data, data)
+	})
+	assertFail(t, "EqualValues", func(t testing.TB) {
+		Same(t, data, &Data{"a", 1})
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		Same(t, data, nil)
+	})
+}
+
+func TestNotSame(t *testing.T) {
+	data := &Data{"a", 1}
+	assertOk(t, "EqualValues", func(t testing.TB) {
+		NotSame(t, data, &Data{"a", 1})
+	})
+	assertFail(t, "SamePointer", func(t testing.TB) {
+		NotSame(t, data, data)
+	})
+}
+
 func TestContains(t *testing.T) {
 	assertOk(t, "Found", func(t testing.TB) {
 		Contains(t, "a haystack with a needle in it", "needle")
@@ -74,144 +559,1468 @@ func TestNotContains(t *testing.T) {
 	})
 }
 
-func TestSliceContains(t *testing.T) {
-	assertOk(t, "Found", func(t testing.TB) {
-		SliceContains(t, []string{"hello", "world"}, "hello")
-	})
-	assertFail(t, "NotFound", func(t testing.TB) {
-		SliceContains(t, []string{"hello", "world"}, "goodbye")
-	})
+func TestNotContainsPositions(t *testing.T) {
+	tests := []struct {
+		name      string
+		haystack  string
+		needle    string
+		positions string
+	}{
+		{"Simple", "a needle in a haystack", "needle", "   ^^^^^^"},
+		{"Escaped", "a\tneedle", "\tneedle", "  ^^^^^^^^"},
+		{"MultiByte", "héllo wörld", "wörld", "       ^^^^^"},
+		{"Emoji", "🙂 needle 🙂", "needle 🙂", "   ^^^^^^^^"},
+		{"MultiByteNeedle", "a 🙂 b 🙂", "🙂", "   ^   ^"},
+		{"Repeated", "needle and needle", "needle", " ^^^^^^     ^^^^^^"},
+		{"Overlapping", "xaaaa", "aa", "  ^^^^"},
+		{"InvalidUTF8", "\xffneedle", "needle", "     ^^^^^^"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			quotedHaystack, _, positions := needlePosition(test.haystack, test.needle)
+			Equal(t, test.positions, positions, "haystack: %s", quotedHaystack)
+		})
+	}
 }
 
-func TestNotSliceContains(t *testing.T) {
-	assertOk(t, "NotFound", func(t testing.TB) {
-		NotSliceContains(t, []string{"hello", "world"}, "goodbye")
+func TestContainsAll(t *testing.T) {
+	assertOk(t, "All", func(t testing.TB) {
+		ContainsAll(t, "started server on port 8080", []string{"server", "8080"})
 	})
-	assertFail(t, "Found", func(t testing.TB) {
-		NotSliceContains(t, []string{"hello", "world"}, "hello")
+	assertOk(t, "NoNeedles", func(t testing.TB) {
+		ContainsAll(t, "haystack", nil)
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		ContainsAll(t, "started server on port 8080", []string{"server", "9090"})
 	})
+	tester := &testTester{T: t}
+	ContainsAll(tester, "started server", []string{"client", "server", "port"})
+	Equal(t, "Haystack does not contain all needles.\nMissing: [\"client\" \"port\"]\nHaystack: \"started server\"\n", tester.failed)
 }
 
-func TestEqualError(t *testing.T) {
-	assertOk(t, "SameMessage", func(t testing.TB) {
-		EqualError(t, fmt.Errorf("hello"), "hello")
+func TestContainsAny(t *testing.T) {
+	assertOk(t, "One", func(t testing.TB) {
+		ContainsAny(t, "started server on port 8080", []string{"8080", "9090"})
 	})
-	assertOk(t, "Nil", func(t testing.TB) {
-		EqualError(t, nil, "")
+	assertFail(t, "None", func(t testing.TB) {
+		ContainsAny(t, "started server on port 8080", []string{"client", "9090"})
 	})
-	assertFail(t, "MessageMismatch", func(t testing.TB) {
-		EqualError(t, fmt.Errorf("hello"), "goodbye")
+	assertFail(t, "NoNeedles", func(t testing.TB) {
+		ContainsAny(t, "haystack", nil)
 	})
 }
 
-func TestError(t *testing.T) {
-	assertOk(t, "Error", func(t testing.TB) {
-		Error(t, fmt.Errorf("hello"))
+func TestContainsN(t *testing.T) {
+	assertOk(t, "Exact", func(t testing.TB) {
+		ContainsN(t, "a needle, a needle, a needle", "needle", 3)
 	})
-	assertFail(t, "Nil", func(t testing.TB) {
-		Error(t, nil)
+	assertOk(t, "None", func(t testing.TB) {
+		ContainsN(t, "a haystack", "needle", 0)
 	})
-}
-
-func TestNoError(t *testing.T) {
-	assertOk(t, "Nil", func(t testing.TB) {
-		NoError(t, nil)
+	assertFail(t, "TooFew", func(t testing.TB) {
+		ContainsN(t, "a needle, a needle", "needle", 3)
 	})
-	assertFail(t, "Error", func(t testing.TB) {
-		NoError(t, fmt.Errorf("hello"))
+	assertFail(t, "TooMany", func(t testing.TB) {
+		ContainsN(t, "a needle, a needle", "needle", 1)
 	})
 }
 
-func TestZero(t *testing.T) {
-	assertOk(t, "Struct", func(t testing.TB) {
-		Zero(t, Data{})
+func TestContainsNMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	ContainsN(tester, "a needle, a needle", "needle", 3)
+	Equal(t, "Expected haystack to contain needle 3 times but found 2.\nNeedle: \"needle\"\nHaystack: \"a needle, a needle\"\n", tester.failed)
+}
+
+func TestBytesContains(t *testing.T) {
+	assertOk(t, "Found", func(t testing.TB) {
+		BytesContains(t, []byte("a haystack with a needle in it"), []byte("needle"))
 	})
-	assertOk(t, "NilSlice", func(t testing.TB) {
-		var slice []int
-		Zero(t, slice)
+	assertOk(t, "Binary", func(t testing.TB) {
+		BytesContains(t, []byte{0xff, 0x00, 0xfe, 0x01}, []byte{0x00, 0xfe})
 	})
-	assertFail(t, "NonEmptyStruct", func(t testing.TB) {
-		Zero(t, Data{Str: "str"})
+	assertFail(t, "NotFound", func(t testing.TB) {
+		BytesContains(t, []byte("a haystack with a needle in it"), []byte("screw"))
 	})
-	assertFail(t, "NonEmptySlice", func(t testing.TB) {
-		slice := []int{1, 2, 3}
-		Zero(t, slice)
+}
+
+func TestNotBytesContains(t *testing.T) {
+	assertOk(t, "NotFound", func(t testing.TB) {
+		NotBytesContains(t, []byte("a haystack with a needle in it"), []byte("screw"))
 	})
-	assertOk(t, "ZeroLenSlice", func(t testing.TB) {
-		slice := []int{}
-		Zero(t, slice)
+	assertFail(t, "Found", func(t testing.TB) {
+		NotBytesContains(t, []byte("a haystack with a needle in it"), []byte("needle"))
 	})
 }
 
-func TestNotZero(t *testing.T) {
-	assertOk(t, "PopulatedStruct", func(t testing.TB) {
-		notZero := Data{Str: "hello"}
-		NotZero(t, notZero)
-	})
-	assertFail(t, "EmptyStruct", func(t testing.TB) {
-		zero := Data{}
-		NotZero(t, zero)
+func TestNotBytesContainsMessage(t *testing.T) {
+	haystack := []byte("0123456789abcdef0123456789abcdef\xff\x00needle\x01 and some more bytes after it")
+	tester := &testTester{T: t}
+	NotBytesContains(tester, haystack, []byte("\x00needle"))
+	Equal(t, `Haystack should not contain needle.
+Needle:
+00000000  00 6e 65 65 64 6c 65                              |.needle|
+Haystack at offset 0x21:
+00000010  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+00000020  ff 00 6e 65 65 64 6c 65  01 20 61 6e 64 20 73 6f  |..needle. and so|
+             ^^ ^^ ^^ ^^ ^^ ^^ ^^
+00000030  6d 65 20 6d 6f 72 65 20  62 79 74 65 73 20 61 66  |me more bytes af|
+`, tester.failed)
+}
+
+func TestRegexp(t *testing.T) {
+	assertOk(t, "Match", func(t testing.TB) {
+		Regexp(t, `^hello \w+$`, "hello world")
 	})
-	assertFail(t, "NilSlice", func(t testing.TB) {
-		var slice []int
-		NotZero(t, slice)
+	assertOk(t, "Compiled", func(t testing.TB) {
+		Regexp(t, regexp.MustCompile(`wor`), "hello world")
 	})
-	assertFail(t, "ZeroLenSlice", func(t testing.TB) {
-		slice := []int{}
-		NotZero(t, slice)
+	assertFail(t, "NoMatch", func(t testing.TB) {
+		Regexp(t, `^world`, "hello world")
 	})
-	assertOk(t, "Slice", func(t testing.TB) {
-		slice := []int{1, 2, 3}
-		NotZero(t, slice)
+	assertFail(t, "InvalidPattern", func(t testing.TB) {
+		Regexp(t, `(`, "hello world")
 	})
+	tester := &testTester{T: t}
+	Regexp(tester, (*regexp.Regexp)(nil), "hello world")
+	Equal(t, "Invalid regexp pattern:\nnil pattern", tester.failed)
 }
 
-func TestIsError(t *testing.T) {
-	assertOk(t, "SameError", func(t testing.TB) {
-		IsError(t, fmt.Errorf("os error: %w", os.ErrClosed), os.ErrClosed)
+func TestNotRegexp(t *testing.T) {
+	assertOk(t, "NoMatch", func(t testing.TB) {
+		NotRegexp(t, `^world`, "hello world")
 	})
-	assertFail(t, "DifferentError", func(t testing.TB) {
-		IsError(t, fmt.Errorf("not an os error"), os.ErrClosed)
+	assertFail(t, "Match", func(t testing.TB) {
+		NotRegexp(t, regexp.MustCompile(`o\s*w`), "hello world")
+	})
+	assertFail(t, "InvalidPattern", func(t testing.TB) {
+		NotRegexp(t, `(`, "hello world")
+	})
+	assertFail(t, "NilPattern", func(t testing.TB) {
+		NotRegexp(t, (*regexp.Regexp)(nil), "hello world")
 	})
 }
 
-func TestInvalidFormatMsg(t *testing.T) {
-	Panics(t, func() {
-		NotZero(t, Data{}, 123)
-	})
+func TestNotRegexpPosition(t *testing.T) {
+	tester := &testTester{T: t}
+	NotRegexp(tester, `o\s*w`, "hello\tworld")
+	Equal(t, "Expected string to not match pattern:\nPattern: o\\s*w\nString: \"hello\\tworld\"\n             ^^^^\n", tester.failed)
 }
 
-func TestNotIsError(t *testing.T) {
-	assertFail(t, "SameError", func(t testing.TB) {
-		NotIsError(t, fmt.Errorf("os error: %w", os.ErrClosed), os.ErrClosed)
+func TestSliceContains(t *testing.T) {
+	assertOk(t, "Found", func(t testing.TB) {
+		SliceContains(t, []string{"hello", "world"}, "hello")
 	})
-	assertOk(t, "DifferentError", func(t testing.TB) {
-		NotIsError(t, fmt.Errorf("not an os error"), os.ErrClosed)
+	assertFail(t, "NotFound", func(t testing.TB) {
+		SliceContains(t, []string{"hello", "world"}, "goodbye")
 	})
 }
 
-func TestDiff(t *testing.T) {
-	Equal(t, "-before\n+after\n", Diff("before", "after"))
+func TestSliceContainsClosest(t *testing.T) {
+	tester := &testTester{T: t}
+	haystack := []Model{
+		{ID: 1, Name: "alice", Children: []*Model{{ID: 10}}},
+		{ID: 2, Name: "bob"},
+		{ID: 3, Name: "carol", Children: []*Model{{ID: 30}}},
+	}
+	SliceContains(tester, haystack, Model{ID: 2, Name: "bobby"})
+	HasSuffix(t, tester.failed, `Closest element [1]:
+ assert.Model{
+   ID: 2,
+-  Name: "bobby",
++  Name: "bob",
+ }
+`)
+	tester = &testTester{T: t}
+	SliceContains(tester, []Model{}, Model{ID: 2})
+	NotContains(t, tester.failed, "Closest")
 }
 
-func TestHasSuffix(t *testing.T) {
-	assertOk(t, "Suffix", func(t testing.TB) {
-		HasSuffix(t, "hello", "lo")
+func TestNotSliceContains(t *testing.T) {
+	assertOk(t, "NotFound", func(t testing.TB) {
+		NotSliceContains(t, []string{"hello", "world"}, "goodbye")
 	})
-	assertFail(t, "NoSuffix", func(t testing.TB) {
-		HasSuffix(t, "hello", "world")
+	assertFail(t, "Found", func(t testing.TB) {
+		NotSliceContains(t, []string{"hello", "world"}, "hello")
 	})
 }
 
-func TestHasPrefix(t *testing.T) {
-	assertOk(t, "Prefix", func(t testing.TB) {
-		HasPrefix(t, "hello", "he")
+func TestSliceContainsN(t *testing.T) {
+	assertOk(t, "Exact", func(t testing.TB) {
+		SliceContainsN(t, []string{"a", "b", "a"}, "a", 2)
 	})
-	assertFail(t, "NoPrefix", func(t testing.TB) {
-		HasPrefix(t, "hello", "world")
+	assertOk(t, "None", func(t testing.TB) {
+		SliceContainsN(t, []string{"a", "b"}, "c", 0)
+	})
+	assertOk(t, "Structs", func(t testing.TB) {
+		SliceContainsN(t, []Data{{"a", 1}, {"a", 2}, {"a", 1}}, Data{"a", 1}, 2)
+	})
+	assertFail(t, "Different", func(t testing.TB) {
+		SliceContainsN(t, []string{"a", "b", "a"}, "a", 1)
 	})
 }
 
+func TestMapContainsKey(t *testing.T) {
+	assertOk(t, "Found", func(t testing.TB) {
+		MapContainsKey(t, map[string]int{"a": 1, "b": 2}, "a")
+	})
+	assertFail(t, "NotFound", func(t testing.TB) {
+		MapContainsKey(t, map[string]int{"a": 1, "b": 2}, "c")
+	})
+	assertFail(t, "NilMap", func(t testing.TB) {
+		MapContainsKey(t, map[string]int(nil), "a")
+	})
+}
+
+func TestMapContainsValue(t *testing.T) {
+	assertOk(t, "Found", func(t testing.TB) {
+		MapContainsValue(t, map[string]Data{"a": {"a", 1}, "b": {"b", 2}}, Data{"b", 2})
+	})
+	assertFail(t, "NotFound", func(t testing.TB) {
+		MapContainsValue(t, map[string]Data{"a": {"a", 1}, "b": {"b", 2}}, Data{"b", 3})
+	})
+}
+
+func TestMapContains(t *testing.T) {
+	headers := map[string]string{"Content-Type": "text/plain", "Content-Length": "5", "Server": "test"}
+	assertOk(t, "Subset", func(t testing.TB) {
+		MapContains(t, headers, map[string]string{"Content-Type": "text/plain", "Server": "test"})
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		MapContains(t, headers, nil)
+	})
+	assertFail(t, "MissingKey", func(t testing.TB) {
+		MapContains(t, headers, map[string]string{"Location": "/"})
+	})
+	assertFail(t, "DifferentValue", func(t testing.TB) {
+		MapContains(t, headers, map[string]string{"Content-Type": "text/html"})
+	})
+	assertOk(t, "CompareOptions", func(t testing.TB) {
+		MapContains(t, headers, map[string]string{"Content-Type": "TEXT/PLAIN"}, IgnoreCase())
+	})
+	tester := &testTester{T: t}
+	MapContains(tester, map[string]Data{"a": {"a", 1}, "c": {"c", 3}, "d": {"d", 4}}, map[string]Data{"b": {"b", 2}, "c": {"c", 4}})
+	Equal(t, `Map does not contain subset:
+Missing keys:
+  "b": assert.Data{
+    Str: "b",
+    Num: 2,
+  }
+Different values:
+  "c":
+     assert.Data{
+       Str: "c",
+    -  Num: 4,
+    +  Num: 3,
+     }
+`, tester.failed)
+}
+
+func TestMapEqual(t *testing.T) {
+	assertOk(t, "InsertionOrder", func(t testing.TB) {
+		expected := map[string]int{}
+		actual := map[string]int{}
+		for i := 0; i < 100; i++ {
+			expected[strconv.Itoa(i)] = i
+			actual[strconv.Itoa(99-i)] = 99 - i
+		}
+		MapEqual(t, expected, actual)
+	})
+	assertFail(t, "DifferentValue", func(t testing.TB) {
+		MapEqual(t, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1, "b": 3})
+	})
+	assertFail(t, "MissingKey", func(t testing.TB) {
+		MapEqual(t, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 1})
+	})
+	assertOk(t, "NilAndEmpty", func(t testing.TB) {
+		MapEqual(t, nil, map[string]int{})
+	})
+	assertOk(t, "CompareOptions", func(t testing.TB) {
+		MapEqual(t, map[string]Model{"a": {ID: 1, Name: "a"}}, map[string]Model{"a": {ID: 1, Name: "b"}}, ExcludeFields("Name"))
+	})
+}
+
+func TestMapEqualMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	expected := map[string]Data{"a": {"a", 1}, "b": {"b", 2}, "c": {"c", 3}}
+	actual := map[string]Data{"a": {"a", 1}, "c": {"c", 4}, "d": {"d", 4}}
+	MapEqual(tester, expected, actual)
+	Equal(t, `Expected maps to be equal:
+Only in expected:
+  "b": assert.Data{
+    Str: "b",
+    Num: 2,
+  }
+Only in actual:
+  "d": assert.Data{
+    Str: "d",
+    Num: 4,
+  }
+Different values:
+  "c":
+     assert.Data{
+       Str: "c",
+    -  Num: 3,
+    +  Num: 4,
+     }
+`, tester.failed)
+}
+
+func TestElementsMatch(t *testing.T) {
+	assertOk(t, "SameOrder", func(t testing.TB) {
+		ElementsMatch(t, []int{1, 2, 3}, []int{1, 2, 3})
+	})
+	assertOk(t, "DifferentOrder", func(t testing.TB) {
+		ElementsMatch(t, []Data{{"a", 1}, {"b", 2}}, []Data{{"b", 2}, {"a", 1}})
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		ElementsMatch(t, []int{}, nil)
+	})
+	assertFail(t, "DifferentMultiplicity", func(t testing.TB) {
+		ElementsMatch(t, []string{"a", "a", "b"}, []string{"a", "b", "b"})
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		ElementsMatch(t, []int{1, 2, 3}, []int{3, 1})
+	})
+}
+
+func TestPermutation(t *testing.T) {
+	assertOk(t, "Identity", func(t testing.TB) {
+		Equal(t, []int{0, 1, 2}, Permutation(t, []string{"a", "b", "c"}, []string{"a", "b", "c"}))
+	})
+	assertOk(t, "Reordered", func(t testing.TB) {
+		Equal(t, []int{2, 0, 1}, Permutation(t, []string{"a", "b", "c"}, []string{"b", "c", "a"}))
+	})
+	assertOk(t, "Duplicates", func(t testing.TB) {
+		Equal(t, []int{1, 0, 2, 3}, Permutation(t, []string{"a", "b", "a", "b"}, []string{"b", "a", "a", "b"}))
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		Equal(t, []int{}, Permutation[int](t, nil, nil))
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		Permutation(t, []string{"a", "b", "c"}, []string{"b", "a"})
+	})
+	assertFail(t, "DuplicateCount", func(t testing.TB) {
+		Permutation(t, []string{"a", "a", "b"}, []string{"a", "b", "b"})
+	})
+	tester := &testTester{T: t}
+	Permutation(tester, []string{"a", "b"}, []string{"b", "c"})
+	Equal(t, "Expected a permutation:\nMissing: []string{\n  \"a\",\n}\nExtra: []string{\n  \"c\",\n}\n", tester.failed)
+}
+
+func TestElementsMatchMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	ElementsMatch(tester, []string{"a", "a", "b"}, []string{"a", "b", "b"})
+	Equal(t, "Expected elements to match:\nMissing: []string{\n  \"a\",\n}\nExtra: []string{\n  \"b\",\n}\n", tester.failed)
+}
+
+func TestWithinDuration(t *testing.T) {
+	now := time.Now()
+	assertOk(t, "Same", func(t testing.TB) {
+		WithinDuration(t, now, now.Round(0), 0)
+	})
+	assertOk(t, "Within", func(t testing.TB) {
+		WithinDuration(t, now, now.Add(-time.Second), time.Second)
+	})
+	assertFail(t, "Before", func(t testing.TB) {
+		WithinDuration(t, now, now.Add(-2*time.Second), time.Second)
+	})
+	assertFail(t, "After", func(t testing.TB) {
+		WithinDuration(t, now, now.Add(2*time.Second), time.Second)
+	})
+}
+
+func TestSubset(t *testing.T) {
+	assertOk(t, "Subset", func(t testing.TB) {
+		Subset(t, []Data{{"a", 1}, {"b", 2}, {"c", 3}}, []Data{{"c", 3}, {"a", 1}})
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		Subset(t, []int{1, 2}, nil)
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		Subset(t, []int{1, 2, 3}, []int{2, 4, 5})
+	})
+}
+
+func TestSuperset(t *testing.T) {
+	assertOk(t, "Superset", func(t testing.TB) {
+		Superset(t, []int{1, 3}, []int{1, 2, 3})
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		Superset(t, []int{1, 4}, []int{1, 2, 3})
+	})
+}
+
+func TestSubsetMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	Subset(tester, []int{1, 2, 3}, []int{2, 4, 5})
+	Equal(t, "Expected list to contain all elements of subset:\nMissing: []int{\n  4,\n  5,\n}\n", tester.failed)
+}
+
+func TestAllMatch(t *testing.T) {
+	even := func(n int) bool { return n%2 == 0 }
+	assertOk(t, "AllMatch", func(t testing.TB) {
+		AllMatch(t, []int{2, 4, 6}, even)
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		AllMatch(t, []int{}, even)
+	})
+	assertFail(t, "SomeDoNotMatch", func(t testing.TB) {
+		AllMatch(t, []int{2, 3, 4}, even)
+	})
+}
+
+func TestAllMatchMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	AllMatch(tester, []int{2, 3, 5}, func(n int) bool { return n%2 == 0 })
+	Equal(t, "Expected all elements to match predicate:\nElement 1: 3\nList: []int{\n  2,\n  3,\n  5,\n}\n", tester.failed)
+}
+
+func TestEachEqual(t *testing.T) {
+	type cell struct {
+		Value int
+		Dirty bool
+	}
+	assertOk(t, "AllEqual", func(t testing.TB) {
+		EachEqual(t, []cell{{}, {}, {}}, cell{})
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		EachEqual(t, []cell{}, cell{Value: 1})
+	})
+	assertOk(t, "CompareOptions", func(t testing.TB) {
+		EachEqual(t, []cell{{Dirty: true}, {}}, cell{}, ExcludeFields("Dirty"))
+	})
+	assertFail(t, "OneDiffers", func(t testing.TB) {
+		EachEqual(t, []cell{{}, {Dirty: true}, {}}, cell{})
+	})
+	tester := &testTester{T: t}
+	EachEqual(tester, []cell{{}, {}, {Value: 2}, {Value: 3}}, cell{})
+	Equal(t, "Expected all elements to be equal to expected value:\nElement 2 of 4 differs:\n assert.cell{\n+  Value: 2,\n }\n", tester.failed)
+}
+
+func TestAnyMatch(t *testing.T) {
+	even := func(n int) bool { return n%2 == 0 }
+	assertOk(t, "SomeMatch", func(t testing.TB) {
+		AnyMatch(t, []int{1, 2, 3}, even)
+	})
+	assertFail(t, "NoneMatch", func(t testing.TB) {
+		AnyMatch(t, []int{1, 3, 5}, even)
+	})
+	assertFail(t, "Empty", func(t testing.TB) {
+		AnyMatch(t, []int{}, even)
+	})
+}
+
+func TestUnique(t *testing.T) {
+	assertOk(t, "Unique", func(t testing.TB) {
+		Unique(t, []int{1, 2, 3})
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		Unique(t, []string{})
+	})
+	assertFail(t, "Duplicate", func(t testing.TB) {
+		Unique(t, []string{"a", "b", "a"})
+	})
+}
+
+func TestUniqueFunc(t *testing.T) {
+	byID := func(m Model) int { return m.ID }
+	assertOk(t, "Unique", func(t testing.TB) {
+		UniqueFunc(t, []Model{{ID: 1, Name: "a"}, {ID: 2, Name: "a"}}, byID)
+	})
+	assertFail(t, "Duplicate", func(t testing.TB) {
+		UniqueFunc(t, []Model{{ID: 1, Name: "a"}, {ID: 1, Name: "b"}}, byID)
+	})
+}
+
+func TestUniqueMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	Unique(tester, []string{"a", "b", "c", "b", "a", "b"})
+	Equal(t, "Expected list elements to be unique:\nDuplicate: \"b\"\nIndices: [1 3 5]\n", tester.failed)
+}
+
+func TestSorted(t *testing.T) {
+	assertOk(t, "Sorted", func(t testing.TB) {
+		Sorted(t, []int{1, 2, 2, 3})
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		Sorted(t, []string{})
+	})
+	assertFail(t, "NotSorted", func(t testing.TB) {
+		Sorted(t, []string{"a", "c", "b"})
+	})
+}
+
+func TestSortedFunc(t *testing.T) {
+	byID := func(a, b Model) bool { return a.ID < b.ID }
+	assertOk(t, "Sorted", func(t testing.TB) {
+		SortedFunc(t, []Model{{ID: 1}, {ID: 2}, {ID: 2}}, byID)
+	})
+	assertFail(t, "NotSorted", func(t testing.TB) {
+		SortedFunc(t, []Model{{ID: 2}, {ID: 1}}, byID)
+	})
+}
+
+func TestSortedMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	Sorted(tester, []int{1, 3, 5, 4, 2})
+	Equal(t, "Expected list to be sorted:\nElement 3: 4\nis less than element 2: 5\n", tester.failed)
+}
+
+func TestEqualError(t *testing.T) {
+	assertOk(t, "SameMessage", func(t testing.TB) {
+		EqualError(t, fmt.Errorf("hello"), "hello")
+	})
+	assertOk(t, "Nil", func(t testing.TB) {
+		EqualError(t, nil, "")
+	})
+	assertFail(t, "MessageMismatch", func(t testing.TB) {
+		EqualError(t, fmt.Errorf("hello"), "goodbye")
+	})
+}
+
+func TestErrorContains(t *testing.T) {
+	assertOk(t, "Contains", func(t testing.TB) {
+		ErrorContains(t, fmt.Errorf("open /tmp/foo: no such file"), "no such file")
+	})
+	assertFail(t, "NotContains", func(t testing.TB) {
+		ErrorContains(t, fmt.Errorf("open /tmp/foo: no such file"), "permission denied")
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		ErrorContains(t, nil, "no such file")
+	})
+}
+
+func TestError(t *testing.T) {
+	assertOk(t, "Error", func(t testing.TB) {
+		Error(t, fmt.Errorf("hello"))
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		Error(t, nil)
+	})
+}
+
+func TestNoError(t *testing.T) {
+	assertOk(t, "Nil", func(t testing.TB) {
+		NoError(t, nil)
+	})
+	assertFail(t, "Error", func(t testing.TB) {
+		NoError(t, fmt.Errorf("hello"))
+	})
+}
+
+func TestMust(t *testing.T) {
+	assertOk(t, "Nil", func(t testing.TB) {
+		Equal(t, 42, Must(t, 42, nil))
+	})
+	assertFail(t, "Error", func(t testing.TB) {
+		Must(t, 42, fmt.Errorf("hello"))
+	})
+}
+
+func TestMust2(t *testing.T) {
+	assertOk(t, "Nil", func(t testing.TB) {
+		a, b := Must2(t, "key", 42, nil)
+		Equal(t, "key", a)
+		Equal(t, 42, b)
+	})
+	assertFail(t, "Error", func(t testing.TB) {
+		Must2(t, "key", 42, fmt.Errorf("hello"))
+	})
+}
+
+func TestMust3(t *testing.T) {
+	assertOk(t, "Nil", func(t testing.TB) {
+		a, b, c := Must3(t, "key", 42, true, nil)
+		Equal(t, "key", a)
+		Equal(t, 42, b)
+		Equal(t, true, c)
+	})
+	assertFail(t, "Error", func(t testing.TB) {
+		Must3(t, "key", 42, true, fmt.Errorf("hello"))
+	})
+}
+
+func TestThat(t *testing.T) {
+	assertOk(t, "Satisfied", func(t testing.TB) {
+		That(t, 7, func(n int) bool { return n > 5 })
+	})
+	assertFail(t, "NotSatisfied", func(t testing.TB) {
+		That(t, 3, func(n int) bool { return n > 5 })
+	})
+	tester := &testTester{T: t}
+	That(tester, Data{"a", 1}, func(d Data) bool { return d.Num > 5 }, "expected a large number")
+	Equal(t, "expected a large number\nassert.Data{\n  Str: \"a\",\n  Num: 1,\n}", tester.failed)
+}
+
+func TestFail(t *testing.T) {
+	assertFail(t, "Fail", func(t testing.TB) {
+		Fail(t)
+	})
+	assertFail(t, "FailNow", func(t testing.TB) {
+		FailNow(t)
+	})
+	tester := &testTester{T: t}
+	Fail(tester, "unexpected value %d", 42, Dump("state", "x"))
+	Equal(t, "unexpected value 42\nstate: \"x\"\n", tester.failed)
+	tester = &testTester{T: t}
+	FailNow(tester)
+	Equal(t, "Failed", tester.failed)
+}
+
+func TestNoErrorTrue(t *testing.T) {
+	assertOk(t, "True", func(t testing.TB) {
+		NoErrorTrue(t, true, nil)
+	})
+	assertFail(t, "False", func(t testing.TB) {
+		NoErrorTrue(t, false, nil)
+	})
+	assertFail(t, "Error", func(t testing.TB) {
+		NoErrorTrue(t, true, fmt.Errorf("hello"))
+	})
+	tester := &testTester{T: t}
+	NoErrorTrue(tester, false, fmt.Errorf("hello"))
+	Equal(t, "Did not expect an error but got:\nhello", tester.failed)
+	tester = &testTester{T: t}
+	NoErrorTrue(tester, false, nil)
+	Equal(t, "Expected expression to be true", tester.failed)
+}
+
+func TestZero(t *testing.T) {
+	assertOk(t, "Struct", func(t testing.TB) {
+		Zero(t, Data{})
+	})
+	assertOk(t, "NilSlice", func(t testing.TB) {
+		var slice []int
+		Zero(t, slice)
+	})
+	assertFail(t, "NonEmptyStruct", func(t testing.TB) {
+		Zero(t, Data{Str: "str"})
+	})
+	assertFail(t, "NonEmptySlice", func(t testing.TB) {
+		slice := []int{1, 2, 3}
+		Zero(t, slice)
+	})
+	assertOk(t, "ZeroLenSlice", func(t testing.TB) {
+		slice := []int{}
+		Zero(t, slice)
+	})
+}
+
+func TestNotZero(t *testing.T) {
+	assertOk(t, "PopulatedStruct", func(t testing.TB) {
+		notZero := Data{Str: "hello"}
+		NotZero(t, notZero)
+	})
+	assertFail(t, "EmptyStruct", func(t testing.TB) {
+		zero := Data{}
+		NotZero(t, zero)
+	})
+	assertFail(t, "NilSlice", func(t testing.TB) {
+		var slice []int
+		NotZero(t, slice)
+	})
+	assertFail(t, "ZeroLenSlice", func(t testing.TB) {
+		slice := []int{}
+		NotZero(t, slice)
+	})
+	assertOk(t, "Slice", func(t testing.TB) {
+		slice := []int{1, 2, 3}
+		NotZero(t, slice)
+	})
+}
+
+func TestLen(t *testing.T) {
+	assertOk(t, "Slice", func(t testing.TB) {
+		Len(t, []int{1, 2, 3}, 3)
+	})
+	assertOk(t, "NilSlice", func(t testing.TB) {
+		var slice []int
+		Len(t, slice, 0)
+	})
+	assertOk(t, "Array", func(t testing.TB) {
+		Len(t, [2]string{"a", "b"}, 2)
+	})
+	assertOk(t, "Map", func(t testing.TB) {
+		Len(t, map[string]int{"a": 1}, 1)
+	})
+	assertOk(t, "String", func(t testing.TB) {
+		Len(t, "hello", 5)
+	})
+	assertOk(t, "Chan", func(t testing.TB) {
+		ch := make(chan int, 2)
+		ch <- 1
+		Len(t, ch, 1)
+	})
+	assertFail(t, "WrongLength", func(t testing.TB) {
+		Len(t, []int{1, 2, 3}, 2)
+	})
+	assertFail(t, "Int", func(t testing.TB) {
+		Len(t, 42, 0)
+	})
+	assertFail(t, "Struct", func(t testing.TB) {
+		Len(t, Data{}, 0)
+	})
+}
+
+func TestLenBetween(t *testing.T) {
+	assertOk(t, "Within", func(t testing.TB) {
+		LenBetween(t, []int{1, 2, 3}, 1, 5)
+	})
+	assertOk(t, "Bounds", func(t testing.TB) {
+		LenBetween(t, "ab", 2, 2)
+	})
+	assertFail(t, "TooShort", func(t testing.TB) {
+		LenBetween(t, map[string]int{}, 1, 5)
+	})
+	assertFail(t, "TooLong", func(t testing.TB) {
+		LenBetween(t, []int{1, 2, 3}, 1, 2)
+	})
+	assertFail(t, "InvalidRange", func(t testing.TB) {
+		LenBetween(t, []int{1, 2, 3}, 5, 1)
+	})
+	assertFail(t, "Int", func(t testing.TB) {
+		LenBetween(t, 42, 0, 1)
+	})
+	tester := &testTester{T: t}
+	LenBetween(tester, "abc", 1, 2)
+	Equal(t, "Expected collection length to be in range:\nRange: [1, 2]\nActual: 3\nCollection: \"abc\"\n", tester.failed)
+	LenBetween(tester, "abc", 2, 1, "length of %s", "name")
+	Equal(t, "length of name\nLower bound: 2\nUpper bound: 1\n", tester.failed)
+}
+
+func TestEmpty(t *testing.T) {
+	assertOk(t, "EmptyString", func(t testing.TB) {
+		Empty(t, "")
+	})
+	assertOk(t, "NilSlice", func(t testing.TB) {
+		var slice []int
+		Empty(t, slice)
+	})
+	assertOk(t, "ZeroLenSlice", func(t testing.TB) {
+		Empty(t, []int{})
+	})
+	assertOk(t, "EmptyMap", func(t testing.TB) {
+		Empty(t, map[string]int{})
+	})
+	assertOk(t, "EmptyChan", func(t testing.TB) {
+		Empty(t, make(chan int, 1))
+	})
+	assertOk(t, "ZeroLenArray", func(t testing.TB) {
+		Empty(t, [0]int{})
+	})
+	assertOk(t, "NilPointer", func(t testing.TB) {
+		var data *Data
+		Empty(t, data)
+	})
+	assertFail(t, "ZeroStruct", func(t testing.TB) {
+		Empty(t, Data{})
+	})
+	assertFail(t, "String", func(t testing.TB) {
+		Empty(t, "hello")
+	})
+	assertFail(t, "Slice", func(t testing.TB) {
+		Empty(t, []int{1})
+	})
+}
+
+func TestNotEmpty(t *testing.T) {
+	assertOk(t, "String", func(t testing.TB) {
+		NotEmpty(t, "hello")
+	})
+	assertOk(t, "ZeroStruct", func(t testing.TB) {
+		NotEmpty(t, Data{})
+	})
+	assertOk(t, "Pointer", func(t testing.TB) {
+		NotEmpty(t, &Data{})
+	})
+	assertFail(t, "EmptyString", func(t testing.TB) {
+		NotEmpty(t, "")
+	})
+	assertFail(t, "EmptyMap", func(t testing.TB) {
+		NotEmpty(t, map[string]int{})
+	})
+	assertFail(t, "NilPointer", func(t testing.TB) {
+		var data *Data
+		NotEmpty(t, data)
+	})
+}
+
+func TestNil(t *testing.T) {
+	assertOk(t, "Nil", func(t testing.TB) {
+		Nil(t, nil)
+	})
+	assertOk(t, "TypedNilPointer", func(t testing.TB) {
+		var buf *bytes.Buffer
+		Nil(t, buf)
+	})
+	assertOk(t, "TypedNilInterface", func(t testing.TB) {
+		var w io.Writer = (*bytes.Buffer)(nil)
+		Nil(t, w)
+	})
+	assertOk(t, "NilMap", func(t testing.TB) {
+		var m map[string]int
+		Nil(t, m)
+	})
+	assertOk(t, "NilSlice", func(t testing.TB) {
+		var slice []int
+		Nil(t, slice)
+	})
+	assertOk(t, "NilChan", func(t testing.TB) {
+		var ch chan int
+		Nil(t, ch)
+	})
+	assertOk(t, "NilFunc", func(t testing.TB) {
+		var fn func()
+		Nil(t, fn)
+	})
+	assertFail(t, "Pointer", func(t testing.TB) {
+		Nil(t, &bytes.Buffer{})
+	})
+	assertFail(t, "EmptySlice", func(t testing.TB) {
+		Nil(t, []int{})
+	})
+	assertFail(t, "Int", func(t testing.TB) {
+		Nil(t, 0)
+	})
+}
+
+func TestNotNil(t *testing.T) {
+	assertOk(t, "Pointer", func(t testing.TB) {
+		NotNil(t, &bytes.Buffer{})
+	})
+	assertOk(t, "Int", func(t testing.TB) {
+		NotNil(t, 0)
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		NotNil(t, nil)
+	})
+	assertFail(t, "TypedNilInterface", func(t testing.TB) {
+		var w io.Writer = (*bytes.Buffer)(nil)
+		NotNil(t, w)
+	})
+}
+
+func TestNotNilMessageIncludesType(t *testing.T) {
+	tester := &testTester{T: t}
+	NotNil(tester, (*bytes.Buffer)(nil))
+	Equal(t, "Expected a non-nil value but got:\n(*bytes.Buffer)(nil)", tester.failed)
+}
+
+func TestIsType(t *testing.T) {
+	assertOk(t, "SameType", func(t testing.TB) {
+		IsType[Data](t, Data{})
+	})
+	assertOk(t, "Pointer", func(t testing.TB) {
+		IsType[*Data](t, &Data{})
+	})
+	assertFail(t, "PointerAndValue", func(t testing.TB) {
+		IsType[Data](t, &Data{})
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		IsType[*Data](t, nil)
+	})
+	assertFail(t, "Interface", func(t testing.TB) {
+		IsType[io.Writer](t, &bytes.Buffer{})
+	})
+}
+
+func TestImplements(t *testing.T) {
+	assertOk(t, "Implements", func(t testing.TB) {
+		Implements[io.Writer](t, &bytes.Buffer{})
+	})
+	assertFail(t, "DoesNotImplement", func(t testing.TB) {
+		Implements[io.Writer](t, Data{})
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		Implements[io.Writer](t, nil)
+	})
+	Panics(t, func() {
+		Implements[Data](t, Data{})
+	})
+}
+
+func TestGreater(t *testing.T) {
+	assertOk(t, "Int", func(t testing.TB) {
+		Greater(t, 5, 3)
+	})
+	assertOk(t, "Float", func(t testing.TB) {
+		Greater(t, 1.5, 1.25)
+	})
+	assertOk(t, "String", func(t testing.TB) {
+		Greater(t, "b", "a")
+	})
+	assertFail(t, "Equal", func(t testing.TB) {
+		Greater(t, 3, 3)
+	})
+	assertFail(t, "Less", func(t testing.TB) {
+		Greater(t, 3, 5)
+	})
+}
+
+func TestGreaterOrEqual(t *testing.T) {
+	assertOk(t, "Greater", func(t testing.TB) {
+		GreaterOrEqual(t, 5, 3)
+	})
+	assertOk(t, "Equal", func(t testing.TB) {
+		GreaterOrEqual(t, 3, 3)
+	})
+	assertFail(t, "Less", func(t testing.TB) {
+		GreaterOrEqual(t, 3, 5)
+	})
+}
+
+func TestLess(t *testing.T) {
+	assertOk(t, "Int", func(t testing.TB) {
+		Less(t, 3, 5)
+	})
+	assertFail(t, "Equal", func(t testing.TB) {
+		Less(t, 3, 3)
+	})
+	assertFail(t, "Greater", func(t testing.TB) {
+		Less(t, "b", "a")
+	})
+}
+
+func TestLessOrEqual(t *testing.T) {
+	assertOk(t, "Less", func(t testing.TB) {
+		LessOrEqual(t, 3, 5)
+	})
+	assertOk(t, "Equal", func(t testing.TB) {
+		LessOrEqual(t, 3.0, 3.0)
+	})
+	assertFail(t, "Greater", func(t testing.TB) {
+		LessOrEqual(t, 5, 3)
+	})
+}
+
+func TestOrderingMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	Greater(tester, 3, 5)
+	Equal(t, "Ordering assertion failed:\nExpected 3 > 5", tester.failed)
+}
+
+func TestCompareOrdered(t *testing.T) {
+	cmp := (*big.Int).Cmp
+	assertOk(t, "Less", func(t testing.TB) {
+		CompareOrdered(t, big.NewInt(1), big.NewInt(2), cmp, -1)
+	})
+	assertOk(t, "Equal", func(t testing.TB) {
+		CompareOrdered(t, big.NewInt(2), big.NewInt(2), cmp, 0)
+	})
+	assertOk(t, "Greater", func(t testing.TB) {
+		CompareOrdered(t, big.NewInt(3), big.NewInt(2), cmp, 1)
+	})
+	assertOk(t, "Magnitude", func(t testing.TB) {
+		CompareOrdered(t, "b", "a", func(a, b string) int { return int(a[0]) - int(b[0]) }, 1)
+	})
+	assertFail(t, "NotLess", func(t testing.TB) {
+		CompareOrdered(t, big.NewInt(2), big.NewInt(2), cmp, -1)
+	})
+	assertFail(t, "InvalidWant", func(t testing.TB) {
+		CompareOrdered(t, big.NewInt(1), big.NewInt(2), cmp, -2)
+	})
+}
+
+func TestCompareOrderedMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	CompareOrdered(tester, "b", "a", strings.Compare, -1)
+	Equal(t, "Ordering assertion failed:\nExpected \"b\" < \"a\"\nComparison: 1", tester.failed)
+	CompareOrdered(tester, "a", "b", strings.Compare, 2)
+	Equal(t, "Invalid comparison result, must be -1, 0 or 1:\nWant: 2\n", tester.failed)
+	CompareOrdered(tester, "a", "b", strings.Compare, -2, "sorting %s", "names", Dump("names", []string{"a", "b"}))
+	Equal(t, "sorting names\nWant: -2\nnames: []string{\n  \"a\",\n  \"b\",\n}\n", tester.failed)
+}
+
+func TestBetween(t *testing.T) {
+	assertOk(t, "Inside", func(t testing.TB) {
+		Between(t, 5, 1, 10)
+	})
+	assertOk(t, "Bounds", func(t testing.TB) {
+		Between(t, 1, 1, 10)
+		Between(t, 10, 1, 10)
+	})
+	assertOk(t, "Duration", func(t testing.TB) {
+		Between(t, 50*time.Millisecond, 0, time.Second)
+	})
+	assertFail(t, "Below", func(t testing.TB) {
+		Between(t, 0, 1, 10)
+	})
+	assertFail(t, "Above", func(t testing.TB) {
+		Between(t, "z", "a", "m")
+	})
+	assertFail(t, "InvalidRange", func(t testing.TB) {
+		Between(t, 5, 10, 1)
+	})
+}
+
+func TestBetweenExclusive(t *testing.T) {
+	assertOk(t, "Inside", func(t testing.TB) {
+		BetweenExclusive(t, 5, 1, 10)
+	})
+	assertFail(t, "LowerBound", func(t testing.TB) {
+		BetweenExclusive(t, 1, 1, 10)
+	})
+	assertFail(t, "UpperBound", func(t testing.TB) {
+		BetweenExclusive(t, 10.0, 1, 10)
+	})
+}
+
+func TestBetweenMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	BetweenExclusive(tester, 10, 1, 10)
+	Equal(t, "Expected value to be in range:\nValue: 10\nRange: (1, 10)\n", tester.failed)
+	Between(tester, 5, 10, 1)
+	Equal(t, "Invalid range, lower bound is greater than upper bound:\nLower bound: 10\nUpper bound: 1\n", tester.failed)
+	Between(tester, 5, 10, 1, "bounds for %s", "retries", Dump("config", "x"))
+	Equal(t, "bounds for retries\nLower bound: 10\nUpper bound: 1\nconfig: \"x\"\n", tester.failed)
+}
+
+func TestPositive(t *testing.T) {
+	assertOk(t, "Int", func(t testing.TB) {
+		Positive(t, 1)
+	})
+	assertOk(t, "Float", func(t testing.TB) {
+		Positive(t, 0.5)
+	})
+	assertFail(t, "Zero", func(t testing.TB) {
+		Positive(t, 0)
+	})
+	assertFail(t, "Negative", func(t testing.TB) {
+		Positive(t, int8(-1))
+	})
+	assertFail(t, "NaN", func(t testing.TB) {
+		Positive(t, math.NaN())
+	})
+}
+
+func TestNegative(t *testing.T) {
+	assertOk(t, "Int", func(t testing.TB) {
+		Negative(t, -1)
+	})
+	assertOk(t, "Float", func(t testing.TB) {
+		Negative(t, float32(-0.5))
+	})
+	assertFail(t, "Zero", func(t testing.TB) {
+		Negative(t, 0.0)
+	})
+	assertFail(t, "Positive", func(t testing.TB) {
+		Negative(t, 1)
+	})
+}
+
+func TestInDelta(t *testing.T) {
+	assertOk(t, "Equal", func(t testing.TB) {
+		InDelta(t, 1.0, 1.0, 0)
+	})
+	assertOk(t, "WithinDelta", func(t testing.TB) {
+		InDelta(t, 0.3, 0.1+0.2, 1e-9)
+	})
+	assertOk(t, "AtDelta", func(t testing.TB) {
+		InDelta(t, 1.0, 1.5, 0.5)
+	})
+	assertOk(t, "SameInf", func(t testing.TB) {
+		InDelta(t, math.Inf(1), math.Inf(1), 0.1)
+	})
+	assertFail(t, "OutsideDelta", func(t testing.TB) {
+		InDelta(t, 1.0, 1.2, 0.1)
+	})
+	assertFail(t, "NaN", func(t testing.TB) {
+		InDelta(t, math.NaN(), math.NaN(), 1)
+	})
+	assertFail(t, "OppositeInf", func(t testing.TB) {
+		InDelta(t, math.Inf(1), math.Inf(-1), math.Inf(1))
+	})
+	assertFail(t, "InfAndFinite", func(t testing.TB) {
+		InDelta(t, math.Inf(1), 1, math.Inf(1))
+	})
+}
+
+func TestFloatEqual(t *testing.T) {
+	assertOk(t, "Equal", func(t testing.TB) {
+		FloatEqual(t, 0.5, 0.5)
+	})
+	assertOk(t, "NaN", func(t testing.TB) {
+		FloatEqual(t, math.NaN(), math.Float64frombits(0x7ff8000000000002))
+	})
+	assertOk(t, "SignedZero", func(t testing.TB) {
+		FloatEqual(t, 0, math.Copysign(0, -1))
+	})
+	assertOk(t, "Inf", func(t testing.TB) {
+		FloatEqual(t, math.Inf(-1), math.Inf(-1))
+	})
+	assertFail(t, "NaNAndNumber", func(t testing.TB) {
+		FloatEqual(t, math.NaN(), 0)
+	})
+	assertFail(t, "OppositeInf", func(t testing.TB) {
+		FloatEqual(t, math.Inf(1), math.Inf(-1))
+	})
+	assertFail(t, "NextAfter", func(t testing.TB) {
+		FloatEqual(t, 1, math.Nextafter(1, 2))
+	})
+	a, b := 0.1, 0.2
+	tester := &testTester{T: t}
+	FloatEqual(tester, 0.3, a+b)
+	Equal(t, "Expected floats to be equal:\nExpected: 0.3 (0x3fd3333333333333)\nActual:   0.30000000000000004 (0x3fd3333333333334)\n", tester.failed)
+}
+
+func TestSliceInDelta(t *testing.T) {
+	assertOk(t, "Within", func(t testing.TB) {
+		SliceInDelta(t, []float64{1, 2, 3}, []float64{1.05, 1.95, 3}, 0.1)
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		SliceInDelta(t, nil, []float64{}, 0.1)
+	})
+	assertFail(t, "Outside", func(t testing.TB) {
+		SliceInDelta(t, []float64{1, 2, 3}, []float64{1, 2.5, 3}, 0.1)
+	})
+	assertFail(t, "DifferentLength", func(t testing.TB) {
+		SliceInDelta(t, []float64{1, 2, 3}, []float64{1, 2}, 0.1)
+	})
+	assertFail(t, "NaN", func(t testing.TB) {
+		SliceInDelta(t, []float64{math.NaN()}, []float64{math.NaN()}, 0.1)
+	})
+	tester := &testTester{T: t}
+	SliceInDelta(tester, []float64{1, 2, 3, 4}, []float64{1, 2, 3.5, 5}, 0.25)
+	Equal(t, "Expected element 2 to be within delta:\nExpected: 3\nActual: 3.5\nDifference: 0.5\nDelta: 0.25\n", tester.failed)
+}
+
+func TestNotErrorContains(t *testing.T) {
+	assertOk(t, "Nil", func(t testing.TB) {
+		NotErrorContains(t, nil, "hello")
+	})
+	assertOk(t, "NotContains", func(t testing.TB) {
+		NotErrorContains(t, fmt.Errorf("goodbye world"), "hello")
+	})
+	assertFail(t, "Contains", func(t testing.TB) {
+		NotErrorContains(t, fmt.Errorf("hello world"), "hello")
+	})
+}
+
+func TestIsError(t *testing.T) {
+	assertOk(t, "SameError", func(t testing.TB) {
+		IsError(t, fmt.Errorf("os error: %w", os.ErrClosed), os.ErrClosed)
+	})
+	assertFail(t, "DifferentError", func(t testing.TB) {
+		IsError(t, fmt.Errorf("not an os error"), os.ErrClosed)
+	})
+}
+
+func TestErrorAs(t *testing.T) {
+	assertOk(t, "SameType", func(t testing.TB) {
+		pathErr := ErrorAs[*fs.PathError](t, fmt.Errorf("wrapped: %w", &fs.PathError{Op: "open", Path: "/tmp", Err: os.ErrNotExist}))
+		Equal(t, "/tmp", pathErr.Path)
+	})
+	assertFail(t, "DifferentType", func(t testing.TB) {
+		ErrorAs[*fs.PathError](t, fmt.Errorf("not a path error"))
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		ErrorAs[*fs.PathError](t, nil)
+	})
+}
+
+func TestInvalidFormatMsg(t *testing.T) {
+	tester := &testTester{T: t}
+	NotZero(tester, Data{}, 123, "abc")
+	Equal(t, "Did not expect the zero value: 123 abc\nassert.Data{}", tester.failed)
+}
+
+func TestLazyFormatMsg(t *testing.T) {
+	called := false
+	lazy := func() string {
+		called = true
+		return "lazy message"
+	}
+	True(t, true, lazy)
+	False(t, called)
+	tester := &testTester{T: t}
+	True(tester, false, lazy)
+	True(t, called)
+	Equal(t, "lazy message", tester.failed)
+}
+
+func TestDump(t *testing.T) {
+	tester := &testTester{T: t}
+	Equal(tester, 1, 1, Dump("request", Data{Str: "a"}))
+	Equal(t, "", tester.failed)
+	tester = &testTester{T: t}
+	Contains(tester, "haystack", "needle", "custom %s", "message", Dump("request", Data{Str: "a"}), Dump("count", 2))
+	Equal(t, "custom message\nNeedle: \"needle\"\nHaystack: \"haystack\"\nrequest: assert.Data{\n  Str: \"a\",\n}\ncount: 2\n", tester.failed)
+	tester = &testTester{T: t}
+	True(tester, false, Dump("value", "x"))
+	Equal(t, "Expected expression to be true\nvalue: \"x\"\n", tester.failed)
+}
+
+func TestNotIsError(t *testing.T) {
+	assertFail(t, "SameError", func(t testing.TB) {
+		NotIsError(t, fmt.Errorf("os error: %w", os.ErrClosed), os.ErrClosed)
+	})
+	assertOk(t, "DifferentError", func(t testing.TB) {
+		NotIsError(t, fmt.Errorf("not an os error"), os.ErrClosed)
+	})
+}
+
+type multiError []error
+
+func (m multiError) Error() string   { return fmt.Sprintf("%d errors", len(m)) }
+func (m multiError) Unwrap() []error { return m }
+
+type opaqueError struct{ err error }
+
+func (o opaqueError) Error() string { return "request failed" }
+func (o opaqueError) Unwrap() error { return o.err }
+
+func TestErrorTreeContains(t *testing.T) {
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		ErrorTreeContains(t, fmt.Errorf("hello world"), "world")
+	})
+	assertOk(t, "Wrapped", func(t testing.TB) {
+		ErrorTreeContains(t, fmt.Errorf("outer: %w", opaqueError{os.ErrClosed}), "already closed")
+	})
+	assertOk(t, "MultiError", func(t testing.TB) {
+		ErrorTreeContains(t, multiError{os.ErrExist, opaqueError{os.ErrClosed}}, "already closed")
+	})
+	assertFail(t, "Nil", func(t testing.TB) {
+		ErrorTreeContains(t, nil, "hello")
+	})
+	assertFail(t, "NotContained", func(t testing.TB) {
+		ErrorTreeContains(t, multiError{os.ErrExist, opaqueError{os.ErrClosed}}, "permission denied")
+	})
+	tester := &testTester{T: t}
+	ErrorTreeContains(tester, fmt.Errorf("outer: %w", opaqueError{os.ErrExist}), "closed")
+	Equal(t, `No error in tree contains substring.
+Substring: "closed"
+*fmt.wrapError: outer: request failed
+  assert.opaqueError: request failed
+    *errors.errorString: file already exists
+`, tester.failed)
+}
+
+func TestIsErrorMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	err := fmt.Errorf("outer: %w", fmt.Errorf("middle: %w", os.ErrClosed))
+	NotIsError(tester, err, os.ErrClosed)
+	Equal(t, `Error tree should NOT contain error "file already closed":
+*fmt.wrapError: outer: middle: file already closed
+  *fmt.wrapError: middle: file already closed
+    *errors.errorString: file already closed  <-- matches
+`, tester.failed)
+	tester = &testTester{T: t}
+	IsError(tester, fmt.Errorf("outer: %w", os.ErrExist), os.ErrClosed)
+	Equal(t, `Error tree should contain error "file already closed":
+*fmt.wrapError: outer: file already exists
+  *errors.errorString: file already exists
+`, tester.failed)
+	tester = &testTester{T: t}
+	NotIsError(tester, multiError{os.ErrExist, os.ErrClosed}, os.ErrClosed)
+	Equal(t, `Error tree should NOT contain error "file already closed":
+assert.multiError: 2 errors
+  *errors.errorString: file already exists
+  *errors.errorString: file already closed  <-- matches
+`, tester.failed)
+}
+
+func TestPanics(t *testing.T) {
+	assertOk(t, "Panics", func(t testing.TB) {
+		Panics(t, func() { panic("boom") })
+	})
+	assertFail(t, "DoesNotPanic", func(t testing.TB) {
+		Panics(t, func() {})
+	})
+	assertFail(t, "NilFunction", func(t testing.TB) {
+		Panics(t, nil)
+	})
+	Equal(t, any("boom"), Panics(t, func() { panic("boom") }))
+	tester := &testTester{T: t}
+	Panics(tester, nil)
+	Equal(t, "assert.Panics called with nil function", tester.failed)
+}
+
+func TestNotPanics(t *testing.T) {
+	assertOk(t, "DoesNotPanic", func(t testing.TB) {
+		NotPanics(t, func() {})
+	})
+	assertFail(t, "Panics", func(t testing.TB) {
+		NotPanics(t, func() { panic("boom") })
+	})
+	assertFail(t, "NilFunction", func(t testing.TB) {
+		NotPanics(t, nil)
+	})
+}
+
+func TestNotPanicsResult(t *testing.T) {
+	assertOk(t, "DoesNotPanic", func(t testing.TB) {
+		Equal(t, 42, NotPanicsResult(t, func() int { return 42 }))
+	})
+	assertFail(t, "Panics", func(t testing.TB) {
+		NotPanicsResult(t, func() int { panic("boom") })
+	})
+	tester := &testTester{T: t}
+	NotPanicsResult(tester, func() int { panic("boom") })
+	HasPrefix(t, tester.failed, "Expected function not to panic\nPanic: boom\ngoroutine ")
+	Contains(t, tester.failed, "TestNotPanicsResult")
+}
+
+func TestPanicsWithValue(t *testing.T) {
+	assertOk(t, "SameValue", func(t testing.TB) {
+		PanicsWithValue(t, Data{"panic", 1}, func() { panic(Data{"panic", 1}) })
+	})
+	assertFail(t, "DifferentValue", func(t testing.TB) {
+		PanicsWithValue(t, "expected", func() { panic("actual") })
+	})
+	assertFail(t, "DifferentType", func(t testing.TB) {
+		PanicsWithValue(t, "expected", func() { panic(fmt.Errorf("expected")) })
+	})
+	assertFail(t, "NoPanic", func(t testing.TB) {
+		PanicsWithValue(t, "expected", func() {})
+	})
+}
+
+func TestPanicsWithError(t *testing.T) {
+	assertOk(t, "SameError", func(t testing.TB) {
+		PanicsWithError(t, "boom", func() { panic(fmt.Errorf("boom")) })
+	})
+	assertFail(t, "DifferentError", func(t testing.TB) {
+		PanicsWithError(t, "boom", func() { panic(fmt.Errorf("bang")) })
+	})
+	assertFail(t, "NotAnError", func(t testing.TB) {
+		PanicsWithError(t, "boom", func() { panic("boom") })
+	})
+	assertFail(t, "NoPanic", func(t testing.TB) {
+		PanicsWithError(t, "boom", func() {})
+	})
+}
+
+var allocSink []byte
+
+func TestMaxAllocs(t *testing.T) {
+	assertOk(t, "NoAllocations", func(t testing.TB) {
+		MaxAllocs(t, 0, func() {})
+	})
+	assertOk(t, "WithinBudget", func(t testing.TB) {
+		MaxAllocs(t, 1, func() { allocSink = make([]byte, 1024) })
+	})
+	assertFail(t, "OverBudget", func(t testing.TB) {
+		MaxAllocs(t, 1, func() {
+			allocSink = make([]byte, 1024)
+			allocSink = make([]byte, 2048)
+		})
+	})
+	tester := &testTester{T: t}
+	MaxAllocs(tester, 0, func() { allocSink = make([]byte, 1024) })
+	Equal(t, "Function allocated more than its budget:\nBudget: 0\nAverage allocations per run: 1\n", tester.failed)
+}
+
+func TestDiff(t *testing.T) {
+	Equal(t, "-before\n+after\n", Diff("before\n", "after\n", DiffContext(0)))
+	Equal(t, "Expected: \"before\"\nActual:   \"after\"\n           ^\n", Diff("before", "after"))
+}
+
+func TestCompareDiff(t *testing.T) {
+	equal, diff := CompareDiff(Data{"a", 1}, Data{"a", 1})
+	True(t, equal)
+	Equal(t, "", diff)
+	equal, diff = CompareDiff(Data{"a", 1}, Data{"a", 2})
+	False(t, equal)
+	Equal(t, Diff(Data{"a", 1}, Data{"a", 2}), diff)
+	equal, _ = CompareDiff(Data{"a", 1}, Data{"a", 2}, Exclude[int64]())
+	True(t, equal)
+}
+
+func TestHasSuffix(t *testing.T) {
+	assertOk(t, "Suffix", func(t testing.TB) {
+		HasSuffix(t, "hello", "lo")
+	})
+	assertFail(t, "NoSuffix", func(t testing.TB) {
+		HasSuffix(t, "hello", "world")
+	})
+}
+
+func TestHasPrefix(t *testing.T) {
+	assertOk(t, "Prefix", func(t testing.TB) {
+		HasPrefix(t, "hello", "he")
+	})
+	assertFail(t, "NoPrefix", func(t testing.TB) {
+		HasPrefix(t, "hello", "world")
+	})
+}
+
+func TestHasPrefixFold(t *testing.T) {
+	assertOk(t, "SameCase", func(t testing.TB) {
+		HasPrefixFold(t, "https://example.com", "https://")
+	})
+	assertOk(t, "DifferentCase", func(t testing.TB) {
+		HasPrefixFold(t, "HTTPS://example.com", "https://")
+	})
+	assertOk(t, "Unicode", func(t testing.TB) {
+		HasPrefixFold(t, "\u212Aelvin", "kel") // KELVIN SIGN folds to k.
+	})
+	assertFail(t, "NoPrefix", func(t testing.TB) {
+		HasPrefixFold(t, "http://example.com", "https://")
+	})
+	assertFail(t, "TooShort", func(t testing.TB) {
+		HasPrefixFold(t, "http", "https://")
+	})
+}
+
+func TestHasSuffixFold(t *testing.T) {
+	assertOk(t, "SameCase", func(t testing.TB) {
+		HasSuffixFold(t, "image.png", ".png")
+	})
+	assertOk(t, "DifferentCase", func(t testing.TB) {
+		HasSuffixFold(t, "IMAGE.PNG", ".png")
+	})
+	assertOk(t, "Unicode", func(t testing.TB) {
+		HasSuffixFold(t, "5 \u212A", " k")
+	})
+	assertFail(t, "NoSuffix", func(t testing.TB) {
+		HasSuffixFold(t, "image.jpg", ".png")
+	})
+	assertFail(t, "TooShort", func(t testing.TB) {
+		HasSuffixFold(t, "png", ".png")
+	})
+}
+
+func TestFailuresAreSingleCalls(t *testing.T) {
+	failures := map[string]func(t testing.TB){
+		"Equal":         func(t testing.TB) { Equal(t, Data{"expected\ntext", 1234}, Data{"actual\ntext", 1234}) },
+		"NotEqual":      func(t testing.TB) { NotEqual(t, Data{"text", 1234}, Data{"text", 1234}) },
+		"Contains":      func(t testing.TB) { Contains(t, "haystack", "needle") },
+		"NotContains":   func(t testing.TB) { NotContains(t, "a needle in a haystack", "needle") },
+		"SliceContains": func(t testing.TB) { SliceContains(t, []Data{{"a", 1}, {"b", 2}}, Data{"b", 3}) },
+		"MapEqual":      func(t testing.TB) { MapEqual(t, map[string]int{"a": 1, "b": 2}, map[string]int{"a": 2, "c": 3}) },
+		"ElementsMatch": func(t testing.TB) { ElementsMatch(t, []int{1, 2, 3}, []int{2, 3, 4}) },
+		"IsError":       func(t testing.TB) { IsError(t, fmt.Errorf("wrapped: %w", io.EOF), io.ErrUnexpectedEOF) },
+		"JSONMatches":   func(t testing.TB) { JSONMatches(t, `{"a": "1", "b": []}`, map[string]any{"a": JSONNumber, "c": 1}) },
+		"Dump":          func(t testing.TB) { Equal(t, 1, 2, Dump("first", Data{"a", 1}), Dump("second", []int{1, 2})) },
+		"Fail":          func(t testing.TB) { Fail(t, "failed") },
+	}
+	for name, fn := range failures {
+		fn := fn
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			for i := 0; i < 10; i++ {
+				recorder := &outputRecorder{T: t}
+				fn(recorder)
+				Equal(t, 1, len(recorder.output), "failure should be reported in a single call")
+			}
+		})
+	}
+}
+
+// outputRecorder records each call that produces test output.
+type outputRecorder struct {
+	*testing.T
+	output []string
+}
+
+func (r *outputRecorder) Log(args ...any)                   { r.record(fmt.Sprint(args...)) }
+func (r *outputRecorder) Logf(format string, args ...any)   { r.record(fmt.Sprintf(format, args...)) }
+func (r *outputRecorder) Error(args ...any)                 { r.record(fmt.Sprint(args...)) }
+func (r *outputRecorder) Errorf(format string, args ...any) { r.record(fmt.Sprintf(format, args...)) }
+func (r *outputRecorder) Fatal(args ...any)                 { r.record(fmt.Sprint(args...)) }
+func (r *outputRecorder) Fatalf(format string, args ...any) { r.record(fmt.Sprintf(format, args...)) }
+func (r *outputRecorder) record(msg string)                 { r.output = append(r.output, msg) }
+
 type testTester struct {
 	*testing.T
 	failed string
@@ -221,6 +2030,10 @@ func (t *testTester) Fatalf(message string, args ...interface{}) {
 	t.failed = fmt.Sprintf(message, args...)
 }
 
+func (t *testTester) Errorf(message string, args ...interface{}) {
+	t.failed = fmt.Sprintf(message, args...)
+}
+
 func (t *testTester) Fatal(args ...interface{}) {
 	t.failed = fmt.Sprint(args...)
 }
@@ -0,0 +1,148 @@
+package assert
+
+import (
+	"testing"
+)
+
+// An Asserter provides the most common assertions as methods bound to a test, and applies
+// default CompareOptions to every comparison it makes, eg.
+//
+//	a := assert.For(t, assert.IgnoreCase())
+//	a.Equal("Alice", user.Name)
+//	a.NoError(err)
+//
+// Go does not allow generic methods, so Equal and NotEqual compare values as "any". Assertions
+// without a method can be passed the bound test from T.
+type Asserter struct {
+	t       testing.TB
+	options []CompareOption
+}
+
+// For returns an Asserter bound to "t".
+//
+// Options passed to an individual assertion are applied after "options".
+func For(t testing.TB, options ...CompareOption) *Asserter {
+	return &Asserter{t: t, options: options}
+}
+
+// T returns the test the Asserter is bound to.
+func (a *Asserter) T() testing.TB { return a.t }
+
+// withOptions prepends the Asserter's default options to the arguments of an assertion, so
+// that options passed to the assertion take precedence.
+func (a *Asserter) withOptions(msgArgsAndCompareOptions []any) []any {
+	out := make([]any, 0, len(a.options)+len(msgArgsAndCompareOptions))
+	for _, option := range a.options {
+		out = append(out, option)
+	}
+	return append(out, msgArgsAndCompareOptions...)
+}
+
+// Equal asserts that "expected" and "actual" are equal. See Equal.
+func (a *Asserter) Equal(expected, actual any, msgArgsAndCompareOptions ...any) {
+	a.t.Helper()
+	Equal(a.t, expected, actual, a.withOptions(msgArgsAndCompareOptions)...)
+}
+
+// NotEqual asserts that "expected" is not equal to "actual". See NotEqual.
+func (a *Asserter) NotEqual(expected, actual any, msgArgsAndCompareOptions ...any) {
+	a.t.Helper()
+	NotEqual(a.t, expected, actual, a.withOptions(msgArgsAndCompareOptions)...)
+}
+
+// Contains asserts that "haystack" contains "needle".
+func (a *Asserter) Contains(haystack string, needle string, msgAndArgs ...any) {
+	a.t.Helper()
+	Contains(a.t, haystack, needle, msgAndArgs...)
+}
+
+// NotContains asserts that "haystack" does not contain "needle".
+func (a *Asserter) NotContains(haystack string, needle string, msgAndArgs ...any) {
+	a.t.Helper()
+	NotContains(a.t, haystack, needle, msgAndArgs...)
+}
+
+// Len asserts that a slice, array, map, string or channel has the given length.
+func (a *Asserter) Len(collection any, length int, msgAndArgs ...any) {
+	a.t.Helper()
+	Len(a.t, collection, length, msgAndArgs...)
+}
+
+// Empty asserts that a value is empty. See Empty.
+func (a *Asserter) Empty(value any, msgAndArgs ...any) {
+	a.t.Helper()
+	Empty(a.t, value, msgAndArgs...)
+}
+
+// NotEmpty asserts that a value is not empty. See Empty.
+func (a *Asserter) NotEmpty(value any, msgAndArgs ...any) {
+	a.t.Helper()
+	NotEmpty(a.t, value, msgAndArgs...)
+}
+
+// Nil asserts that a value is nil. See Nil.
+func (a *Asserter) Nil(value any, msgAndArgs ...any) {
+	a.t.Helper()
+	Nil(a.t, value, msgAndArgs...)
+}
+
+// NotNil asserts that a value is not nil. See NotNil.
+func (a *Asserter) NotNil(value any, msgAndArgs ...any) {
+	a.t.Helper()
+	NotNil(a.t, value, msgAndArgs...)
+}
+
+// True asserts that an expression is true.
+func (a *Asserter) True(ok bool, msgAndArgs ...any) {
+	a.t.Helper()
+	True(a.t, ok, msgAndArgs...)
+}
+
+// False asserts that an expression is false.
+func (a *Asserter) False(ok bool, msgAndArgs ...any) {
+	a.t.Helper()
+	False(a.t, ok, msgAndArgs...)
+}
+
+// Error asserts that an error is not nil.
+func (a *Asserter) Error(err error, msgAndArgs ...any) {
+	a.t.Helper()
+	Error(a.t, err, msgAndArgs...)
+}
+
+// NoError asserts that an error is nil.
+func (a *Asserter) NoError(err error, msgAndArgs ...any) {
+	a.t.Helper()
+	NoError(a.t, err, msgAndArgs...)
+}
+
+// EqualError asserts that either an error is non-nil and that its message is what is expected,
+// or that error is nil if the expected message is empty.
+func (a *Asserter) EqualError(err error, errString string, msgAndArgs ...any) {
+	a.t.Helper()
+	EqualError(a.t, err, errString, msgAndArgs...)
+}
+
+// ErrorContains asserts that an error is non-nil and that its message contains "substr".
+func (a *Asserter) ErrorContains(err error, substr string, msgAndArgs ...any) {
+	a.t.Helper()
+	ErrorContains(a.t, err, substr, msgAndArgs...)
+}
+
+// IsError asserts that any error in "err"'s tree matches "target".
+func (a *Asserter) IsError(err, target error, msgAndArgs ...any) {
+	a.t.Helper()
+	IsError(a.t, err, target, msgAndArgs...)
+}
+
+// Panics asserts that the given function panics, and returns the value it panicked with.
+func (a *Asserter) Panics(fn func(), msgAndArgs ...any) any {
+	a.t.Helper()
+	return Panics(a.t, fn, msgAndArgs...)
+}
+
+// NotPanics asserts that the given function does not panic.
+func (a *Asserter) NotPanics(fn func(), msgAndArgs ...any) {
+	a.t.Helper()
+	NotPanics(a.t, fn, msgAndArgs...)
+}
@@ -0,0 +1,41 @@
+package assert
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAsserter(t *testing.T) {
+	assertOk(t, "Equal", func(t testing.TB) {
+		a := For(t)
+		a.Equal("hello", "hello")
+		a.NotEqual("hello", "world")
+		a.NoError(nil)
+		a.Error(errors.New("failed"))
+		a.Len([]int{1, 2}, 2)
+		a.Nil(nil)
+		a.True(true)
+		a.Panics(func() { panic("boom") })
+	})
+	assertFail(t, "NotEqual", func(t testing.TB) {
+		For(t).Equal("hello", "world")
+	})
+	assertFail(t, "NoError", func(t testing.TB) {
+		For(t).NoError(errors.New("failed"))
+	})
+	assertOk(t, "DefaultOptions", func(t testing.TB) {
+		For(t, IgnoreCase()).Equal("Hello", "hello")
+	})
+	assertFail(t, "DefaultOptionsOnlyForAsserter", func(t testing.TB) {
+		For(t, IgnoreCase())
+		Equal(t, "Hello", "hello")
+	})
+}
+
+func TestAsserterMessages(t *testing.T) {
+	tester := &testTester{T: t}
+	a := For(tester)
+	a.Equal("hello", "world", "greeting %d", 1)
+	Equal(t, "greeting 1\nExpected: \"hello\"\nActual:   \"world\"\n           ^\n", tester.failed)
+	True(t, a.T() == tester)
+}
@@ -0,0 +1,124 @@
+package assert
+
+import (
+	"fmt"
+	"testing"
+)
+
+// The functions in this file are variants of the core assertions that take an explicit
+// format string for the failure message, so that "go vet" can check it. The message is
+// only formatted if the assertion fails.
+
+// Equalf is like Equal, but with a formatted failure message.
+func Equalf[T any](t testing.TB, expected, actual T, format string, args ...any) {
+	t.Helper()
+	Equal(t, expected, actual, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// NotEqualf is like NotEqual, but with a formatted failure message.
+func NotEqualf[T any](t testing.TB, expected, actual T, format string, args ...any) {
+	t.Helper()
+	NotEqual(t, expected, actual, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Containsf is like Contains, but with a formatted failure message.
+func Containsf(t testing.TB, haystack string, needle string, format string, args ...any) {
+	t.Helper()
+	Contains(t, haystack, needle, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// NotContainsf is like NotContains, but with a formatted failure message.
+func NotContainsf(t testing.TB, haystack string, needle string, format string, args ...any) {
+	t.Helper()
+	NotContains(t, haystack, needle, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Zerof is like Zero, but with a formatted failure message.
+func Zerof[T any](t testing.TB, value T, format string, args ...any) {
+	t.Helper()
+	Zero(t, value, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// NotZerof is like NotZero, but with a formatted failure message.
+func NotZerof[T any](t testing.TB, value T, format string, args ...any) {
+	t.Helper()
+	NotZero(t, value, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Lenf is like Len, but with a formatted failure message.
+func Lenf[T any](t testing.TB, collection T, length int, format string, args ...any) {
+	t.Helper()
+	Len(t, collection, length, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Emptyf is like Empty, but with a formatted failure message.
+func Emptyf[T any](t testing.TB, value T, format string, args ...any) {
+	t.Helper()
+	Empty(t, value, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// NotEmptyf is like NotEmpty, but with a formatted failure message.
+func NotEmptyf[T any](t testing.TB, value T, format string, args ...any) {
+	t.Helper()
+	NotEmpty(t, value, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Nilf is like Nil, but with a formatted failure message.
+func Nilf(t testing.TB, value any, format string, args ...any) {
+	t.Helper()
+	Nil(t, value, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// NotNilf is like NotNil, but with a formatted failure message.
+func NotNilf(t testing.TB, value any, format string, args ...any) {
+	t.Helper()
+	NotNil(t, value, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// EqualErrorf is like EqualError, but with a formatted failure message.
+func EqualErrorf(t testing.TB, err error, errString string, format string, args ...any) {
+	t.Helper()
+	EqualError(t, err, errString, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// ErrorContainsf is like ErrorContains, but with a formatted failure message.
+func ErrorContainsf(t testing.TB, err error, substr string, format string, args ...any) {
+	t.Helper()
+	ErrorContains(t, err, substr, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// IsErrorf is like IsError, but with a formatted failure message.
+func IsErrorf(t testing.TB, err, target error, format string, args ...any) {
+	t.Helper()
+	IsError(t, err, target, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Errorf is like Error, but with a formatted failure message.
+func Errorf(t testing.TB, err error, format string, args ...any) {
+	t.Helper()
+	Error(t, err, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// NoErrorf is like NoError, but with a formatted failure message.
+func NoErrorf(t testing.TB, err error, format string, args ...any) {
+	t.Helper()
+	NoError(t, err, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Truef is like True, but with a formatted failure message.
+func Truef(t testing.TB, ok bool, format string, args ...any) {
+	t.Helper()
+	True(t, ok, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Falsef is like False, but with a formatted failure message.
+func Falsef(t testing.TB, ok bool, format string, args ...any) {
+	t.Helper()
+	False(t, ok, func() string { return fmt.Sprintf(format, args...) })
+}
+
+// Panicsf is like Panics, but with a formatted failure message.
+func Panicsf(t testing.TB, fn func(), format string, args ...any) any {
+	t.Helper()
+	return Panics(t, fn, func() string { return fmt.Sprintf(format, args...) })
+}
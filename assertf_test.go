@@ -0,0 +1,43 @@
+package assert
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFormattedVariants(t *testing.T) {
+	assertOk(t, "Equalf", func(t testing.TB) {
+		Equalf(t, 1, 1, "values for %s", "key")
+	})
+	assertFail(t, "NotEqualf", func(t testing.TB) {
+		NotEqualf(t, 1, 1, "values for %s", "key")
+	})
+	assertFail(t, "NoErrorf", func(t testing.TB) {
+		NoErrorf(t, errors.New("failed"), "opening %s", "file")
+	})
+	assertFail(t, "Truef", func(t testing.TB) {
+		Truef(t, false, "expected %d", 1)
+	})
+}
+
+func TestFormattedVariantsMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	Equalf(tester, 1, 2, "values for %s", "key")
+	Equal(t, "values for key\n-1\n+2\n", tester.failed)
+	Containsf(tester, "haystack", "needle", "searching %d%%", 100)
+	Equal(t, "searching 100%\nNeedle: \"needle\"\nHaystack: \"haystack\"\n", tester.failed)
+}
+
+type countingStringer struct{ calls *int }
+
+func (c countingStringer) String() string {
+	*c.calls++
+	return "value"
+}
+
+func TestFormattedVariantsAreLazy(t *testing.T) {
+	calls := 0
+	Equalf(t, 1, 1, "%s", countingStringer{&calls})
+	NoErrorf(t, nil, "%s", countingStringer{&calls})
+	Equal(t, 0, calls)
+}
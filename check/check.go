@@ -0,0 +1,955 @@
+// Package check provides non-fatal versions of the assertions in package assert.
+//
+// Each function reports failures with t.Error rather than t.Fatal, so the test
+// continues running, and returns true if the assertion passed. Failure messages
+// are identical to those of the corresponding assertion in package assert.
+package check
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/assert/v2"
+	"golang.org/x/exp/constraints"
+)
+
+// nonFatal reports fatal failures as non-fatal errors.
+type nonFatal struct {
+	testing.TB
+	failed bool
+}
+
+func (n *nonFatal) Fatal(args ...any) {
+	n.TB.Helper()
+	n.failed = true
+	n.TB.Error(args...)
+}
+
+func (n *nonFatal) Fatalf(format string, args ...any) {
+	n.TB.Helper()
+	n.failed = true
+	n.TB.Errorf(format, args...)
+}
+
+// HasPrefix asserts that the string s starts with prefix.
+func HasPrefix(t testing.TB, s, prefix string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.HasPrefix(n, s, prefix, msgAndArgs...)
+	return !n.failed
+}
+
+// HasSuffix asserts that the string s ends with suffix.
+func HasSuffix(t testing.TB, s, suffix string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.HasSuffix(n, s, suffix, msgAndArgs...)
+	return !n.failed
+}
+
+// Equal asserts that "expected" and "actual" are equal.
+func Equal[T any](t testing.TB, expected, actual T, msgArgsAndCompareOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Equal(n, expected, actual, msgArgsAndCompareOptions...)
+	return !n.failed
+}
+
+// NotEqual asserts that "expected" is not equal to "actual".
+func NotEqual[T any](t testing.TB, expected, actual T, msgArgsAndCompareOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotEqual(n, expected, actual, msgArgsAndCompareOptions...)
+	return !n.failed
+}
+
+// Contains asserts that "haystack" contains "needle".
+func Contains(t testing.TB, haystack string, needle string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Contains(n, haystack, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// NotContains asserts that "haystack" does not contain "needle".
+func NotContains(t testing.TB, haystack string, needle string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotContains(n, haystack, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// ContainsAll asserts that "haystack" contains every one of "needles", reporting all of
+// those that are missing.
+func ContainsAll(t testing.TB, haystack string, needles []string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ContainsAll(n, haystack, needles, msgAndArgs...)
+	return !n.failed
+}
+
+// ContainsAny asserts that "haystack" contains at least one of "needles".
+func ContainsAny(t testing.TB, haystack string, needles []string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ContainsAny(n, haystack, needles, msgAndArgs...)
+	return !n.failed
+}
+
+// Regexp asserts that the string s matches the regular expression "pattern".
+func Regexp[P assert.Pattern](t testing.TB, pattern P, s string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Regexp(n, pattern, s, msgAndArgs...)
+	return !n.failed
+}
+
+// NotRegexp asserts that the string s does not match the regular expression "pattern".
+func NotRegexp[P assert.Pattern](t testing.TB, pattern P, s string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotRegexp(n, pattern, s, msgAndArgs...)
+	return !n.failed
+}
+
+// SliceContains asserts that "haystack" contains "needle".
+func SliceContains[T any](t testing.TB, haystack []T, needle T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.SliceContains(n, haystack, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// NotSliceContains asserts that "haystack" does not contain "needle".
+func NotSliceContains[T any](t testing.TB, haystack []T, needle T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotSliceContains(n, haystack, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// ElementsMatch asserts that "expected" and "actual" contain the same elements, ignoring order.
+func ElementsMatch[T any](t testing.TB, expected, actual []T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ElementsMatch(n, expected, actual, msgAndArgs...)
+	return !n.failed
+}
+
+// Permutation asserts that "actual" is a permutation of "expected", and returns the index
+// in "actual" of each element of "expected".
+func Permutation[T any](t testing.TB, expected, actual []T, msgAndArgs ...any) ([]int, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	mapping := assert.Permutation(n, expected, actual, msgAndArgs...)
+	return mapping, !n.failed
+}
+
+// Zero asserts that a value is its zero value.
+func Zero[T any](t testing.TB, value T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Zero(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// NotZero asserts that a value is not its zero value.
+func NotZero[T any](t testing.TB, value T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotZero(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// Len asserts that a slice, array, map, string or channel has the given length.
+func Len[T any](t testing.TB, collection T, length int, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Len(n, collection, length, msgAndArgs...)
+	return !n.failed
+}
+
+// LenBetween asserts that a slice, array, map, string or channel has a length in the
+// inclusive range ["lo", "hi"].
+func LenBetween[T any](t testing.TB, collection T, lo, hi int, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.LenBetween(n, collection, lo, hi, msgAndArgs...)
+	return !n.failed
+}
+
+// Empty asserts that a value is empty.
+func Empty[T any](t testing.TB, value T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Empty(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// NotEmpty asserts that a value is not empty.
+func NotEmpty[T any](t testing.TB, value T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotEmpty(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// Nil asserts that a value is nil.
+func Nil(t testing.TB, value any, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Nil(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// NotNil asserts that a value is not nil.
+func NotNil(t testing.TB, value any, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotNil(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// Greater asserts that a is greater than b.
+func Greater[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Greater(n, a, b, msgAndArgs...)
+	return !n.failed
+}
+
+// GreaterOrEqual asserts that a is greater than or equal to b.
+func GreaterOrEqual[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.GreaterOrEqual(n, a, b, msgAndArgs...)
+	return !n.failed
+}
+
+// Less asserts that a is less than b.
+func Less[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Less(n, a, b, msgAndArgs...)
+	return !n.failed
+}
+
+// LessOrEqual asserts that a is less than or equal to b.
+func LessOrEqual[T constraints.Ordered](t testing.TB, a, b T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.LessOrEqual(n, a, b, msgAndArgs...)
+	return !n.failed
+}
+
+// CompareOrdered asserts that the three-way comparison "cmp(a, b)" has the sign of "want",
+// which must be -1, 0 or 1.
+func CompareOrdered[T any](t testing.TB, a, b T, cmp func(a, b T) int, want int, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.CompareOrdered(n, a, b, cmp, want, msgAndArgs...)
+	return !n.failed
+}
+
+// InDelta asserts that "expected" and "actual" are within "delta" of each other.
+func InDelta(t testing.TB, expected, actual, delta float64, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.InDelta(n, expected, actual, delta, msgAndArgs...)
+	return !n.failed
+}
+
+// EqualError asserts that either an error is non-nil and that its message is what is expected,
+// or that error is nil if the expected message is empty.
+func EqualError(t testing.TB, err error, errString string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.EqualError(n, err, errString, msgAndArgs...)
+	return !n.failed
+}
+
+// IsError asserts than any error in "err"'s tree matches "target".
+func IsError(t testing.TB, err, target error, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.IsError(n, err, target, msgAndArgs...)
+	return !n.failed
+}
+
+// NotIsError asserts than no error in "err"'s tree matches "target".
+func NotIsError(t testing.TB, err, target error, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotIsError(n, err, target, msgAndArgs...)
+	return !n.failed
+}
+
+// Error asserts that an error is not nil.
+func Error(t testing.TB, err error, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Error(n, err, msgAndArgs...)
+	return !n.failed
+}
+
+// NoError asserts that an error is nil.
+func NoError(t testing.TB, err error, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NoError(n, err, msgAndArgs...)
+	return !n.failed
+}
+
+// Must asserts that "err" is nil and returns "value".
+func Must[T any](t testing.TB, value T, err error, msgAndArgs ...any) (T, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	value = assert.Must(n, value, err, msgAndArgs...)
+	return value, !n.failed
+}
+
+// Must2 asserts that "err" is nil and returns "a" and "b".
+func Must2[A, B any](t testing.TB, a A, b B, err error, msgAndArgs ...any) (A, B, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	a, b = assert.Must2(n, a, b, err, msgAndArgs...)
+	return a, b, !n.failed
+}
+
+// Must3 asserts that "err" is nil and returns "a", "b" and "c".
+func Must3[A, B, C any](t testing.TB, a A, b B, c C, err error, msgAndArgs ...any) (A, B, C, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	a, b, c = assert.Must3(n, a, b, c, err, msgAndArgs...)
+	return a, b, c, !n.failed
+}
+
+// True asserts that an expression is true.
+func True(t testing.TB, ok bool, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.True(n, ok, msgAndArgs...)
+	return !n.failed
+}
+
+// False asserts that an expression is false.
+func False(t testing.TB, ok bool, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.False(n, ok, msgAndArgs...)
+	return !n.failed
+}
+
+// That asserts that "value" satisfies "predicate", displaying the value if it does not.
+func That[T any](t testing.TB, value T, predicate func(T) bool, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.That(n, value, predicate, msgAndArgs...)
+	return !n.failed
+}
+
+// Panics asserts that the given function panics.
+func Panics(t testing.TB, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Panics(n, fn, msgAndArgs...)
+	return !n.failed
+}
+
+// NotPanics asserts that the given function does not panic.
+func NotPanics(t testing.TB, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotPanics(n, fn, msgAndArgs...)
+	return !n.failed
+}
+
+// ErrorContains asserts that an error is non-nil and that its message contains "substr".
+func ErrorContains(t testing.TB, err error, substr string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ErrorContains(n, err, substr, msgAndArgs...)
+	return !n.failed
+}
+
+// ErrorAs asserts that an error in "err"'s tree matches the type T, and returns it.
+func ErrorAs[T error](t testing.TB, err error, msgAndArgs ...any) (T, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	target := assert.ErrorAs[T](n, err, msgAndArgs...)
+	return target, !n.failed
+}
+
+// NotPanicsResult asserts that the given function does not panic, and returns its result.
+func NotPanicsResult[T any](t testing.TB, fn func() T, msgAndArgs ...any) (T, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	result := assert.NotPanicsResult(n, fn, msgAndArgs...)
+	return result, !n.failed
+}
+
+// PanicsWithValue asserts that the given function panics with a value equal to "expected".
+func PanicsWithValue(t testing.TB, expected any, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.PanicsWithValue(n, expected, fn, msgAndArgs...)
+	return !n.failed
+}
+
+// PanicsWithError asserts that the given function panics with an error whose message is "errString".
+func PanicsWithError(t testing.TB, errString string, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.PanicsWithError(n, errString, fn, msgAndArgs...)
+	return !n.failed
+}
+
+// IsType asserts that the dynamic type of "value" is exactly T.
+func IsType[T any](t testing.TB, value any, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.IsType[T](n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// Implements asserts that the dynamic type of "value" implements the interface I.
+func Implements[I any](t testing.TB, value any, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Implements[I](n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// WithinDuration asserts that "expected" and "actual" are within "delta" of each other.
+func WithinDuration(t testing.TB, expected, actual time.Time, delta time.Duration, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.WithinDuration(n, expected, actual, delta, msgAndArgs...)
+	return !n.failed
+}
+
+// JSONEqual asserts that "expected" and "actual" are semantically equal JSON documents.
+func JSONEqual(t testing.TB, expected, actual string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.JSONEqual(n, expected, actual, msgAndArgs...)
+	return !n.failed
+}
+
+// Eventually asserts that "condition" returns true within "waitFor", checking every "tick".
+func Eventually(t testing.TB, condition func() bool, waitFor time.Duration, tick time.Duration, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Eventually(n, condition, waitFor, tick, msgAndArgs...)
+	return !n.failed
+}
+
+// EventuallyCtx asserts that "condition" returns true before "ctx" is done, checking every
+// "tick".
+func EventuallyCtx(ctx context.Context, t testing.TB, condition func(context.Context) bool, tick time.Duration, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.EventuallyCtx(ctx, n, condition, tick, msgAndArgs...)
+	return !n.failed
+}
+
+// Never asserts that "condition" does not return true within "waitFor", checking every "tick".
+func Never(t testing.TB, condition func() bool, waitFor time.Duration, tick time.Duration, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Never(n, condition, waitFor, tick, msgAndArgs...)
+	return !n.failed
+}
+
+// MapContainsKey asserts that the map "m" contains "key".
+func MapContainsKey[K comparable, V any](t testing.TB, m map[K]V, key K, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.MapContainsKey(n, m, key, msgAndArgs...)
+	return !n.failed
+}
+
+// MapContainsValue asserts that the map "m" contains "value".
+func MapContainsValue[K comparable, V any](t testing.TB, m map[K]V, value V, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.MapContainsValue(n, m, value, msgAndArgs...)
+	return !n.failed
+}
+
+// MapEqual asserts that the maps "expected" and "actual" are equal.
+func MapEqual[K comparable, V any](t testing.TB, expected, actual map[K]V, msgArgsAndCompareOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.MapEqual(n, expected, actual, msgArgsAndCompareOptions...)
+	return !n.failed
+}
+
+// Subset asserts that every element of "subset" is also in "list".
+func Subset[T any](t testing.TB, list, subset []T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Subset(n, list, subset, msgAndArgs...)
+	return !n.failed
+}
+
+// Superset asserts that every element of "list" is also in "superset".
+func Superset[T any](t testing.TB, list, superset []T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Superset(n, list, superset, msgAndArgs...)
+	return !n.failed
+}
+
+// Same asserts that "expected" and "actual" point to the same object.
+func Same[T any](t testing.TB, expected, actual *T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Same(n, expected, actual, msgAndArgs...)
+	return !n.failed
+}
+
+// NotSame asserts that "expected" and "actual" do not point to the same object.
+func NotSame[T any](t testing.TB, expected, actual *T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotSame(n, expected, actual, msgAndArgs...)
+	return !n.failed
+}
+
+// Positive asserts that a value is greater than zero.
+func Positive[T constraints.Signed | constraints.Float](t testing.TB, value T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Positive(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// Negative asserts that a value is less than zero.
+func Negative[T constraints.Signed | constraints.Float](t testing.TB, value T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Negative(n, value, msgAndArgs...)
+	return !n.failed
+}
+
+// Between asserts that "lo" <= "value" <= "hi".
+func Between[T constraints.Ordered](t testing.TB, value, lo, hi T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Between(n, value, lo, hi, msgAndArgs...)
+	return !n.failed
+}
+
+// BetweenExclusive asserts that "lo" < "value" < "hi".
+func BetweenExclusive[T constraints.Ordered](t testing.TB, value, lo, hi T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.BetweenExclusive(n, value, lo, hi, msgAndArgs...)
+	return !n.failed
+}
+
+// BytesContains asserts that "haystack" contains "needle".
+func BytesContains(t testing.TB, haystack, needle []byte, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.BytesContains(n, haystack, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// NotBytesContains asserts that "haystack" does not contain "needle".
+func NotBytesContains(t testing.TB, haystack, needle []byte, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotBytesContains(n, haystack, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// Sorted asserts that "list" is sorted in non-decreasing order.
+func Sorted[T constraints.Ordered](t testing.TB, list []T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Sorted(n, list, msgAndArgs...)
+	return !n.failed
+}
+
+// SortedFunc asserts that "list" is sorted in non-decreasing order according to "less".
+func SortedFunc[T any](t testing.TB, list []T, less func(a, b T) bool, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.SortedFunc(n, list, less, msgAndArgs...)
+	return !n.failed
+}
+
+// Unique asserts that no element of "list" occurs more than once.
+func Unique[T comparable](t testing.TB, list []T, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Unique(n, list, msgAndArgs...)
+	return !n.failed
+}
+
+// UniqueFunc asserts that no two elements of "list" have the same key, as returned by "key".
+func UniqueFunc[T any, K comparable](t testing.TB, list []T, key func(T) K, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.UniqueFunc(n, list, key, msgAndArgs...)
+	return !n.failed
+}
+
+// NotErrorContains asserts that an error is nil or that its message does not contain "substr".
+func NotErrorContains(t testing.TB, err error, substr string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotErrorContains(n, err, substr, msgAndArgs...)
+	return !n.failed
+}
+
+// ContainsN asserts that "haystack" contains exactly "count" non-overlapping instances of "needle".
+func ContainsN(t testing.TB, haystack string, needle string, count int, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ContainsN(n, haystack, needle, count, msgAndArgs...)
+	return !n.failed
+}
+
+// SliceContainsN asserts that "haystack" contains exactly "count" elements equal to "needle".
+func SliceContainsN[T any](t testing.TB, haystack []T, needle T, count int, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.SliceContainsN(n, haystack, needle, count, msgAndArgs...)
+	return !n.failed
+}
+
+// AllMatch asserts that "pred" returns true for every element of "list".
+func AllMatch[T any](t testing.TB, list []T, pred func(T) bool, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.AllMatch(n, list, pred, msgAndArgs...)
+	return !n.failed
+}
+
+// AnyMatch asserts that "pred" returns true for at least one element of "list".
+func AnyMatch[T any](t testing.TB, list []T, pred func(T) bool, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.AnyMatch(n, list, pred, msgAndArgs...)
+	return !n.failed
+}
+
+// NoErrorTrue asserts that "err" is nil and that "ok" is true.
+func NoErrorTrue(t testing.TB, ok bool, err error, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NoErrorTrue(n, ok, err, msgAndArgs...)
+	return !n.failed
+}
+
+// Receives asserts that a value is received from "ch" within "timeout", and returns it.
+func Receives[T any](t testing.TB, ch <-chan T, timeout time.Duration, msgAndArgs ...any) (T, bool) {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	value := assert.Receives(n, ch, timeout, msgAndArgs...)
+	return value, !n.failed
+}
+
+// Closed asserts that "ch" is closed within "timeout".
+func Closed[T any](t testing.TB, ch <-chan T, timeout time.Duration, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Closed(n, ch, timeout, msgAndArgs...)
+	return !n.failed
+}
+
+// HasPrefixFold asserts that the string s starts with prefix, ignoring case.
+func HasPrefixFold(t testing.TB, s, prefix string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.HasPrefixFold(n, s, prefix, msgAndArgs...)
+	return !n.failed
+}
+
+// HasSuffixFold asserts that the string s ends with suffix, ignoring case.
+func HasSuffixFold(t testing.TB, s, suffix string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.HasSuffixFold(n, s, suffix, msgAndArgs...)
+	return !n.failed
+}
+
+// JSONMatches asserts that the JSON document "actual" conforms to "shape".
+func JSONMatches(t testing.TB, actual string, shape any, msgArgsAndJSONOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.JSONMatches(n, actual, shape, msgArgsAndJSONOptions...)
+	return !n.failed
+}
+
+// FileExists asserts that "path" exists and is not a directory.
+func FileExists(t testing.TB, path string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.FileExists(n, path, msgAndArgs...)
+	return !n.failed
+}
+
+// DirExists asserts that "path" exists and is a directory.
+func DirExists(t testing.TB, path string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.DirExists(n, path, msgAndArgs...)
+	return !n.failed
+}
+
+// NoFileExists asserts that nothing exists at "path".
+func NoFileExists(t testing.TB, path string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NoFileExists(n, path, msgAndArgs...)
+	return !n.failed
+}
+
+// FileContains asserts that the contents of the file at "path" contain "needle".
+func FileContains(t testing.TB, path string, needle string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.FileContains(n, path, needle, msgAndArgs...)
+	return !n.failed
+}
+
+// ReaderContains asserts that the data read from "r" contains "needle".
+func ReaderContains(t testing.TB, r io.Reader, needle string, msgArgsAndReaderOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ReaderContains(n, r, needle, msgArgsAndReaderOptions...)
+	return !n.failed
+}
+
+// ReadersEqual asserts that "expected" and "actual" produce identical data.
+func ReadersEqual(t testing.TB, expected, actual io.Reader, msgArgsAndReaderOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ReadersEqual(n, expected, actual, msgArgsAndReaderOptions...)
+	return !n.failed
+}
+
+// Equalf is like Equal, but with a formatted failure message.
+func Equalf[T any](t testing.TB, expected, actual T, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Equalf(n, expected, actual, format, args...)
+	return !n.failed
+}
+
+// NotEqualf is like NotEqual, but with a formatted failure message.
+func NotEqualf[T any](t testing.TB, expected, actual T, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotEqualf(n, expected, actual, format, args...)
+	return !n.failed
+}
+
+// Containsf is like Contains, but with a formatted failure message.
+func Containsf(t testing.TB, haystack string, needle string, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Containsf(n, haystack, needle, format, args...)
+	return !n.failed
+}
+
+// NotContainsf is like NotContains, but with a formatted failure message.
+func NotContainsf(t testing.TB, haystack string, needle string, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotContainsf(n, haystack, needle, format, args...)
+	return !n.failed
+}
+
+// Zerof is like Zero, but with a formatted failure message.
+func Zerof[T any](t testing.TB, value T, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Zerof(n, value, format, args...)
+	return !n.failed
+}
+
+// NotZerof is like NotZero, but with a formatted failure message.
+func NotZerof[T any](t testing.TB, value T, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotZerof(n, value, format, args...)
+	return !n.failed
+}
+
+// Lenf is like Len, but with a formatted failure message.
+func Lenf[T any](t testing.TB, collection T, length int, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Lenf(n, collection, length, format, args...)
+	return !n.failed
+}
+
+// Emptyf is like Empty, but with a formatted failure message.
+func Emptyf[T any](t testing.TB, value T, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Emptyf(n, value, format, args...)
+	return !n.failed
+}
+
+// NotEmptyf is like NotEmpty, but with a formatted failure message.
+func NotEmptyf[T any](t testing.TB, value T, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotEmptyf(n, value, format, args...)
+	return !n.failed
+}
+
+// Nilf is like Nil, but with a formatted failure message.
+func Nilf(t testing.TB, value any, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Nilf(n, value, format, args...)
+	return !n.failed
+}
+
+// NotNilf is like NotNil, but with a formatted failure message.
+func NotNilf(t testing.TB, value any, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NotNilf(n, value, format, args...)
+	return !n.failed
+}
+
+// EqualErrorf is like EqualError, but with a formatted failure message.
+func EqualErrorf(t testing.TB, err error, errString string, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.EqualErrorf(n, err, errString, format, args...)
+	return !n.failed
+}
+
+// ErrorContainsf is like ErrorContains, but with a formatted failure message.
+func ErrorContainsf(t testing.TB, err error, substr string, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ErrorContainsf(n, err, substr, format, args...)
+	return !n.failed
+}
+
+// IsErrorf is like IsError, but with a formatted failure message.
+func IsErrorf(t testing.TB, err, target error, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.IsErrorf(n, err, target, format, args...)
+	return !n.failed
+}
+
+// Errorf is like Error, but with a formatted failure message.
+func Errorf(t testing.TB, err error, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Errorf(n, err, format, args...)
+	return !n.failed
+}
+
+// NoErrorf is like NoError, but with a formatted failure message.
+func NoErrorf(t testing.TB, err error, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.NoErrorf(n, err, format, args...)
+	return !n.failed
+}
+
+// Truef is like True, but with a formatted failure message.
+func Truef(t testing.TB, ok bool, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Truef(n, ok, format, args...)
+	return !n.failed
+}
+
+// Falsef is like False, but with a formatted failure message.
+func Falsef(t testing.TB, ok bool, format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Falsef(n, ok, format, args...)
+	return !n.failed
+}
+
+// Panicsf is like Panics, but with a formatted failure message.
+func Panicsf(t testing.TB, fn func(), format string, args ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.Panicsf(n, fn, format, args...)
+	return !n.failed
+}
+
+// CompletesWithin asserts that "fn" returns within "timeout".
+func CompletesWithin(t testing.TB, timeout time.Duration, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.CompletesWithin(n, timeout, fn, msgAndArgs...)
+	return !n.failed
+}
+
+// SliceInDelta asserts that "expected" and "actual" have the same length, and that each
+// element of "actual" is within "delta" of the corresponding element of "expected".
+func SliceInDelta(t testing.TB, expected, actual []float64, delta float64, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.SliceInDelta(n, expected, actual, delta, msgAndArgs...)
+	return !n.failed
+}
+
+// MapContains asserts that every key in "subset" is present in "m" with an equal value.
+func MapContains[K comparable, V any](t testing.TB, m map[K]V, subset map[K]V, msgArgsAndCompareOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.MapContains(n, m, subset, msgArgsAndCompareOptions...)
+	return !n.failed
+}
+
+// JSONRoundTrips asserts that "value" is equal to the result of marshalling it to JSON and
+// unmarshalling the JSON into a new value of the same type.
+func JSONRoundTrips[T any](t testing.TB, value T, msgArgsAndCompareOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.JSONRoundTrips(n, value, msgArgsAndCompareOptions...)
+	return !n.failed
+}
+
+// MaxAllocs asserts that "fn" makes at most "budget" heap allocations per call.
+func MaxAllocs(t testing.TB, budget int, fn func(), msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.MaxAllocs(n, budget, fn, msgAndArgs...)
+	return !n.failed
+}
+
+// FloatEqual asserts that "expected" and "actual" are the same floating point value.
+func FloatEqual(t testing.TB, expected, actual float64, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.FloatEqual(n, expected, actual, msgAndArgs...)
+	return !n.failed
+}
+
+// EachEqual asserts that every element of "list" is equal to "expected", as with Equal.
+func EachEqual[T any](t testing.TB, list []T, expected T, msgArgsAndCompareOptions ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.EachEqual(n, list, expected, msgArgsAndCompareOptions...)
+	return !n.failed
+}
+
+// ErrorTreeContains asserts that an error is non-nil and that the message of it or any error
+// in its tree contains "substr".
+func ErrorTreeContains(t testing.TB, err error, substr string, msgAndArgs ...any) bool {
+	t.Helper()
+	n := &nonFatal{TB: t}
+	assert.ErrorTreeContains(n, err, substr, msgAndArgs...)
+	return !n.failed
+}
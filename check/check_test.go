@@ -0,0 +1,82 @@
+package check
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type testTester struct {
+	*testing.T
+	errors []string
+	fatal  bool
+}
+
+func (t *testTester) Errorf(message string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(message, args...))
+}
+
+func (t *testTester) Error(args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func (t *testTester) Fatalf(message string, args ...interface{}) {
+	t.fatal = true
+}
+
+func (t *testTester) Fatal(args ...interface{}) {
+	t.fatal = true
+}
+
+func TestCheckContinues(t *testing.T) {
+	tester := &testTester{T: t}
+	assert.False(t, Equal(tester, 1, 2))
+	assert.False(t, NoError(tester, fmt.Errorf("hello")))
+	assert.True(t, True(tester, true))
+	assert.False(t, tester.fatal)
+	assert.Equal(t, 2, len(tester.errors))
+}
+
+func TestCheckMessagesMatchAssert(t *testing.T) {
+	tests := []struct {
+		name   string
+		check  func(t testing.TB)
+		assert func(t testing.TB)
+	}{
+		{"Equal",
+			func(t testing.TB) { Equal(t, "hello\nworld", "goodbye\nworld") },
+			func(t testing.TB) { assert.Equal(t, "hello\nworld", "goodbye\nworld") }},
+		{"NotContains",
+			func(t testing.TB) { NotContains(t, "a haystack with a needle in it", "needle") },
+			func(t testing.TB) { assert.NotContains(t, "a haystack with a needle in it", "needle") }},
+		{"EqualError",
+			func(t testing.TB) { EqualError(t, nil, "hello") },
+			func(t testing.TB) { assert.EqualError(t, nil, "hello") }},
+		{"Panics",
+			func(t testing.TB) { Panics(t, func() {}) },
+			func(t testing.TB) { assert.Panics(t, func() {}) }},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			checkTester := &testTester{T: t}
+			test.check(checkTester)
+			assertTester := &fatalTester{T: t}
+			test.assert(assertTester)
+			assert.Equal(t, []string{assertTester.failed}, checkTester.errors)
+		})
+	}
+}
+
+type fatalTester struct {
+	*testing.T
+	failed string
+}
+
+func (t *fatalTester) Fatalf(message string, args ...interface{}) {
+	t.failed = fmt.Sprintf(message, args...)
+}
+
+func (t *fatalTester) Fatal(args ...interface{}) {
+	t.failed = fmt.Sprint(args...)
+}
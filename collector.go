@@ -0,0 +1,97 @@
+package assert
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+// A Collector records assertion failures rather than aborting the test, and reports all of
+// them together when flushed.
+//
+// Go does not allow generic methods, so rather than mirroring each assertion as a method,
+// a Collector implements testing.TB and is passed to assertions in place of the test, eg.
+//
+//	c := assert.NewCollector(t)
+//	assert.Equal(c, expected.Name, actual.Name)
+//	assert.Equal(c, expected.Age, actual.Age)
+//	c.Flush()
+//
+// A Collector never stops execution. Assertions that would stop the test record their
+// failure and return, and assertions that return a value, such as ErrorAs or Panics, return
+// its zero value, so the code following them keeps running. Where that code depends on an
+// assertion having passed, call Flush to stop the test first, eg.
+//
+//	user, err := store.Get(id)
+//	assert.NoError(c, err)
+//	c.Flush() // Don't dereference a nil user.
+//	assert.Equal(c, "Alice", user.Name)
+type Collector struct {
+	testing.TB
+	failures []string
+}
+
+// NewCollector creates a new Collector for "t".
+//
+// Any failures not yet reported when the test completes are reported automatically.
+func NewCollector(t testing.TB) *Collector {
+	c := &Collector{TB: t}
+	t.Cleanup(c.Flush)
+	return c
+}
+
+// Failed returns true if any failures have been recorded since the last Flush, or if the
+// underlying test has failed.
+func (c *Collector) Failed() bool {
+	return len(c.failures) > 0 || c.TB.Failed()
+}
+
+// Flush fails the test and stops its execution if any failures have been recorded,
+// reporting all of them.
+func (c *Collector) Flush() {
+	c.TB.Helper()
+	if len(c.failures) == 0 {
+		return
+	}
+	failures := c.failures
+	c.failures = nil
+	c.TB.Fatalf("%d assertions failed:\n\n%s", len(failures), strings.Join(failures, "\n\n"))
+}
+
+func (c *Collector) Fatal(args ...any)                 { c.record(fmt.Sprint(args...)) }
+func (c *Collector) Fatalf(format string, args ...any) { c.record(fmt.Sprintf(format, args...)) }
+func (c *Collector) Error(args ...any)                 { c.record(fmt.Sprint(args...)) }
+func (c *Collector) Errorf(format string, args ...any) { c.record(fmt.Sprintf(format, args...)) }
+func (c *Collector) Fail()                             { c.record("Failed") }
+func (c *Collector) FailNow()                          { c.record("Failed") }
+
+func (c *Collector) record(msg string) {
+	msg = strings.TrimSuffix(msg, "\n")
+	if location := callerLocation(); location != "" {
+		msg = location + ": " + msg
+	}
+	c.failures = append(c.failures, msg)
+}
+
+var assertPkgPath = reflect.TypeOf(Collector{}).PkgPath()
+
+// callerLocation returns the file and line of the first caller outside this module's
+// non-test code, ie. the location of the failing assertion.
+func callerLocation() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		frame, more := frames.Next()
+		inAssert := strings.HasPrefix(frame.Function, assertPkgPath+".") || strings.HasPrefix(frame.Function, assertPkgPath+"/")
+		if !inAssert || strings.HasSuffix(frame.File, "_test.go") {
+			return fmt.Sprintf("%s:%d", filepath.Base(frame.File), frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
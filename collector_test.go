@@ -0,0 +1,56 @@
+package assert
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"time"
+)
+
+func TestCollector(t *testing.T) {
+	tester := &testTester{T: t}
+	c := NewCollector(tester)
+	Equal(c, 1, 2)
+	Equal(c, 1, 1)
+	True(c, false)
+	True(t, c.Failed())
+	Equal(t, "", tester.failed)
+	c.Flush()
+	Regexp(t, `^2 assertions failed:
+
+collector_test\.go:\d+: Expected values to be equal:
+-1
+\+2
+
+collector_test\.go:\d+: Expected expression to be true$`, tester.failed)
+	False(t, c.Failed())
+}
+
+func TestCollectorNoFailures(t *testing.T) {
+	tester := &testTester{T: t}
+	c := NewCollector(tester)
+	Equal(c, 1, 1)
+	c.Flush()
+	Equal(t, "", tester.failed)
+}
+
+func TestCollectorContinuesAfterFatal(t *testing.T) {
+	tester := &testTester{T: t}
+	c := NewCollector(tester)
+	var user *Data
+	NotNil(c, user)
+	pathErr := ErrorAs[*fs.PathError](c, errors.New("failed"))
+	value := Panics(c, func() {})
+	ch := make(chan int)
+	close(ch)
+	n := Receives(c, ch, time.Second)
+	// Execution continues after each fatal failure, with zero values returned.
+	True(t, user == nil && pathErr == nil && value == nil && n == 0)
+	Equal(t, "", tester.failed)
+	if c.Failed() {
+		c.Flush()
+		Regexp(t, `^4 assertions failed:`, tester.failed)
+		return
+	}
+	t.Fatal("dependent code should not run after a failure")
+}
@@ -0,0 +1,352 @@
+package assert
+
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"time"
+
+	"github.com/alecthomas/repr"
+)
+
+// A comparator compares values of the types it applies to.
+type comparator struct {
+	applies func(typ reflect.Type) bool
+	equal   func(a, b reflect.Value) bool
+}
+
+var (
+	goStringerType = reflect.TypeOf((*fmt.GoStringer)(nil)).Elem()
+	timeType       = reflect.TypeOf(time.Time{})
+	bigIntType     = reflect.TypeOf(big.Int{})
+	bigRatType     = reflect.TypeOf(big.Rat{})
+	bigFloatType   = reflect.TypeOf(big.Float{})
+)
+
+func isBigType(t reflect.Type) bool {
+	return t == bigIntType || t == bigRatType || t == bigFloatType
+}
+
+// bigEqual compares "x" and "y" with their Cmp methods if both are pointers to the same
+// math/big type.
+func bigEqual(x, y any) (equal bool, ok bool) {
+	switch x := x.(type) {
+	case *big.Int:
+		if y, ok := y.(*big.Int); ok {
+			return x == y || (x != nil && y != nil && x.Cmp(y) == 0), true
+		}
+	case *big.Rat:
+		if y, ok := y.(*big.Rat); ok {
+			return x == y || (x != nil && y != nil && x.Cmp(y) == 0), true
+		}
+	case *big.Float:
+		if y, ok := y.(*big.Float); ok {
+			return x == y || (x != nil && y != nil && x.Cmp(y) == 0), true
+		}
+	}
+	return false, false
+}
+
+// bigString returns the decimal representation of a non-nil pointer to a math/big number.
+func bigString(value any) (string, bool) {
+	switch value := value.(type) {
+	case *big.Int:
+		return value.String(), value != nil
+	case *big.Rat:
+		return value.RatString(), value != nil
+	case *big.Float:
+		return value.Text('g', -1), value != nil
+	}
+	return "", false
+}
+
+// align returns a copy of "actual" in which every value that a comparator considers equal to
+// the corresponding value in "expected" is replaced by that value.
+//
+// This allows values compared with comparators to be rendered and diffed with repr like any
+// other value. Only values of the same type at the same position in both trees are
+// compared, and values that repr renders as a whole, such as time.Time and GoStringers, are
+// not descended into. The original values are never modified.
+func (o *compareOptions) align(expected, actual any) any {
+	if len(o.comparators) == 0 || expected == nil || actual == nil {
+		return actual
+	}
+	a := &aligner{opts: o, seen: map[alignedPointers]reflect.Value{}}
+	return a.value(reflect.ValueOf(expected), reflect.ValueOf(actual)).Interface()
+}
+
+type aligner struct {
+	opts *compareOptions
+	seen map[alignedPointers]reflect.Value
+}
+
+// alignedPointers identifies a pair of pointers that have already been aligned. As with
+// normalisedPointer, the type distinguishes a pointer to a struct from one to its first field.
+type alignedPointers struct {
+	x, y uintptr
+	typ  reflect.Type
+}
+
+func (a *aligner) value(x, y reflect.Value) reflect.Value { // nolint: gocyclo
+	if !x.IsValid() || !y.IsValid() || x.Type() != y.Type() {
+		return y
+	}
+	x, y = readable(x), readable(y)
+	typ := y.Type()
+	if typ.Kind() != reflect.Interface {
+		for _, comparator := range a.opts.comparators {
+			if comparator.applies(typ) {
+				if comparator.equal(x, y) {
+					return x
+				}
+				return y
+			}
+		}
+	}
+	if typ == timeType || isBigType(typ) || (!a.opts.ignoreGoStringer && typ.Implements(goStringerType)) {
+		return y
+	}
+	switch typ.Kind() {
+	case reflect.Ptr:
+		if x.IsNil() || y.IsNil() || x.Pointer() == y.Pointer() {
+			return y
+		}
+		key := alignedPointers{x.Pointer(), y.Pointer(), typ}
+		if out, ok := a.seen[key]; ok {
+			return out
+		}
+		out := reflect.New(typ.Elem())
+		a.seen[key] = out
+		settable(out.Elem()).Set(a.value(x.Elem(), y.Elem()))
+		return out
+
+	case reflect.Interface:
+		if x.IsNil() || y.IsNil() {
+			return y
+		}
+		out := reflect.New(typ).Elem()
+		out.Set(a.value(x.Elem(), y.Elem()))
+		return out
+
+	case reflect.Struct:
+		x = readableAddr(x) // Unexported fields can only be read from addressable structs.
+		out := reflect.New(typ).Elem()
+		out.Set(y)
+		for i := 0; i < typ.NumField(); i++ {
+			settable(out.Field(i)).Set(a.value(x.Field(i), out.Field(i)))
+		}
+		return out
+
+	case reflect.Slice:
+		if x.IsNil() || y.IsNil() {
+			return y
+		}
+		out := reflect.MakeSlice(typ, y.Len(), y.Len())
+		for i := 0; i < y.Len(); i++ {
+			if i < x.Len() {
+				out.Index(i).Set(a.value(x.Index(i), y.Index(i)))
+			} else {
+				out.Index(i).Set(readable(y.Index(i)))
+			}
+		}
+		return out
+
+	case reflect.Array:
+		out := reflect.New(typ).Elem()
+		for i := 0; i < y.Len(); i++ {
+			out.Index(i).Set(a.value(x.Index(i), y.Index(i)))
+		}
+		return out
+
+	case reflect.Map:
+		if x.IsNil() || y.IsNil() {
+			return y
+		}
+		out := reflect.MakeMapWithSize(typ, y.Len())
+		iter := y.MapRange()
+		for iter.Next() {
+			value := readable(iter.Value())
+			if xv := x.MapIndex(iter.Key()); xv.IsValid() {
+				value = a.value(xv, value)
+			}
+			out.SetMapIndex(readable(iter.Key()), value)
+		}
+		return out
+
+	default:
+		return y
+	}
+}
+
+// deepDiff compares two values field by field, returning the paths of up to "limit"
+// differences, eg. ".Users[1].Email".
+//
+// Comparators are used where they apply, functions and channels are compared by identity,
+// and other values that have no further structure are compared by their repr
+// representation.
+func (o *compareOptions) deepDiff(expected, actual any, limit int) []string {
+	d := &deepDiffer{opts: o, limit: limit, visited: map[[2]uintptr]bool{}}
+	d.compare("", addressable(expected), addressable(actual))
+	return d.diffs
+}
+
+type deepDiffer struct {
+	opts    *compareOptions
+	limit   int
+	diffs   []string
+	visited map[[2]uintptr]bool
+}
+
+func (d *deepDiffer) differ(path string) {
+	if path == "" {
+		path = "."
+	}
+	d.diffs = append(d.diffs, path)
+}
+
+func (d *deepDiffer) done() bool {
+	return len(d.diffs) >= d.limit
+}
+
+func (d *deepDiffer) compare(path string, x, y reflect.Value) { // nolint: gocyclo
+	if d.done() {
+		return
+	}
+	if !x.IsValid() || !y.IsValid() {
+		if x.IsValid() != y.IsValid() {
+			d.differ(path)
+		}
+		return
+	}
+	if x.Type() != y.Type() {
+		d.differ(path)
+		return
+	}
+	x, y = readableAddr(x), readableAddr(y)
+	typ := x.Type()
+	if typ.Kind() != reflect.Interface {
+		for _, comparator := range d.opts.comparators {
+			if comparator.applies(typ) {
+				if !comparator.equal(x, y) {
+					d.differ(path)
+				}
+				return
+			}
+		}
+	}
+	if isBigType(typ) {
+		if equal, _ := bigEqual(x.Addr().Interface(), y.Addr().Interface()); !equal {
+			d.differ(path)
+		}
+		return
+	}
+	if typ == timeType || (!d.opts.ignoreGoStringer && typ.Implements(goStringerType)) {
+		d.compareRepr(path, x, y)
+		return
+	}
+	switch x.Kind() {
+	case reflect.Ptr:
+		if x.IsNil() || y.IsNil() {
+			if x.IsNil() != y.IsNil() {
+				d.differ(path)
+			}
+			return
+		}
+		visit := [2]uintptr{x.Pointer(), y.Pointer()}
+		if x.Pointer() == y.Pointer() || d.visited[visit] {
+			return
+		}
+		d.visited[visit] = true
+		d.compare(path, x.Elem(), y.Elem())
+
+	case reflect.Interface:
+		if x.IsNil() || y.IsNil() {
+			if x.IsNil() != y.IsNil() {
+				d.differ(path)
+			}
+			return
+		}
+		d.compare(path, x.Elem(), y.Elem())
+
+	case reflect.Struct:
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if d.opts.exclude[field.Type] {
+				continue
+			}
+			xf, yf := x.Field(i), y.Field(i)
+			if d.opts.omitEmpty && isEmptyField(xf) && isEmptyField(yf) {
+				continue
+			}
+			d.compare(path+"."+field.Name, xf, yf)
+		}
+
+	case reflect.Slice, reflect.Array:
+		if x.Kind() == reflect.Slice && x.IsNil() != y.IsNil() {
+			d.differ(path)
+			return
+		}
+		if x.Len() != y.Len() {
+			d.differ(path)
+			return
+		}
+		for i := 0; i < x.Len(); i++ {
+			d.compare(fmt.Sprintf("%s[%d]", path, i), x.Index(i), y.Index(i))
+		}
+
+	case reflect.Map:
+		if x.IsNil() != y.IsNil() || x.Len() != y.Len() {
+			d.differ(path)
+			return
+		}
+		iter := x.MapRange()
+		for iter.Next() {
+			keyPath := fmt.Sprintf("%s[%s]", path, repr.String(readable(iter.Key()).Interface()))
+			yv := y.MapIndex(iter.Key())
+			if !yv.IsValid() {
+				d.differ(keyPath)
+				continue
+			}
+			d.compare(keyPath, iter.Value(), yv)
+		}
+
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		// These all render identically regardless of their value.
+		if x.Pointer() != y.Pointer() {
+			d.differ(path)
+		}
+
+	default:
+		d.compareRepr(path, x, y)
+	}
+}
+
+func (d *deepDiffer) compareRepr(path string, x, y reflect.Value) {
+	if repr.String(x.Interface(), d.opts.reprOptions...) != repr.String(y.Interface(), d.opts.reprOptions...) {
+		d.differ(path)
+	}
+}
+
+// isEmptyField matches the definition of empty used by repr.OmitEmpty.
+func isEmptyField(v reflect.Value) bool {
+	return v.IsZero() || (v.Kind() == reflect.Slice || v.Kind() == reflect.Map) && v.Len() == 0
+}
+
+// addressable returns an addressable copy of value, so that unexported fields can be read.
+func addressable(value any) reflect.Value {
+	if value == nil {
+		return reflect.Value{}
+	}
+	return readableAddr(reflect.ValueOf(value))
+}
+
+// readableAddr returns a readable version of v, copying it if necessary so that it is addressable.
+func readableAddr(v reflect.Value) reflect.Value {
+	v = readable(v)
+	if v.CanAddr() {
+		return v
+	}
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+	return out
+}
@@ -0,0 +1,205 @@
+package assert
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithComparator(t *testing.T) {
+	sameInstant := WithComparator(func(a, b time.Time) bool { return a.Equal(b) })
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("AEST", 10*60*60))
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, utc, local, sameInstant)
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, &Model{ID: 1, CreatedAt: utc}, &Model{ID: 1, CreatedAt: local}, sameInstant)
+	})
+	assertOk(t, "String", func(t testing.TB) {
+		Equal(t, []string{"Hello"}, []string{"HELLO"}, WithComparator(strings.EqualFold))
+	})
+	assertOk(t, "Interface", func(t testing.TB) {
+		type stringer interface{ String() string }
+		Equal(t, []any{time.Second}, []any{time.Second}, WithComparator(func(a, b stringer) bool { return a.String() == b.String() }))
+	})
+	assertOk(t, "FirstComparatorWins", func(t testing.TB) {
+		Equal(t, "a", "b", WithComparator(func(a, b string) bool { return true }), WithComparator(func(a, b string) bool { return false }))
+	})
+	assertOk(t, "Unexported", func(t testing.TB) {
+		Equal(t, []any{private{name: "Hello"}}, []any{private{name: "hello"}}, WithComparator(strings.EqualFold))
+	})
+	assertFail(t, "Different", func(t testing.TB) {
+		Equal(t, &Model{ID: 1, CreatedAt: utc}, &Model{ID: 1, CreatedAt: utc.Add(time.Second)}, sameInstant)
+	})
+	assertFail(t, "OtherFieldDiffers", func(t testing.TB) {
+		Equal(t, &Model{ID: 1, CreatedAt: utc}, &Model{ID: 2, CreatedAt: local}, sameInstant)
+	})
+	assertFail(t, "NotEqual", func(t testing.TB) {
+		NotEqual(t, utc, local, sameInstant)
+	})
+}
+
+func TestComparatorsOnlyAffectTheirTypes(t *testing.T) {
+	type handler struct {
+		Name string
+		Func func()
+	}
+	a, b := handler{"a", func() {}}, handler{"A", func() {}}
+	// Functions render identically, so are equal without a comparator and must remain so.
+	assertOk(t, "NoComparator", func(t testing.TB) {
+		Equal(t, handler{"a", a.Func}, handler{"a", b.Func})
+	})
+	assertOk(t, "Comparator", func(t testing.TB) {
+		Equal(t, a, b, WithComparator(strings.EqualFold))
+	})
+	assertOk(t, "EmptySlices", func(t testing.TB) {
+		type list struct{ Items []string }
+		Equal(t, list{Items: []string{}}, list{}, WithComparator(strings.EqualFold))
+	})
+}
+
+func TestComparatorDiff(t *testing.T) {
+	sameInstant := WithComparator(func(a, b time.Time) bool { return a.Equal(b) })
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("AEST", 10*60*60))
+	tester := &testTester{T: t}
+	Equal(tester, &Model{ID: 1, CreatedAt: utc}, &Model{ID: 2, CreatedAt: local}, sameInstant)
+	Contains(t, tester.failed, "-  ID: 1,\n+  ID: 2,\n")
+	NotContains(t, tester.failed, "AEST")
+}
+
+func TestTransform(t *testing.T) {
+	rfc3339 := Transform("UTC", func(t time.Time) string { return t.UTC().Format(time.RFC3339) })
+	utc := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	local := utc.In(time.FixedZone("AEST", 10*60*60))
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, utc, local, rfc3339)
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, map[string]*Model{"a": {ID: 1, CreatedAt: utc}}, map[string]*Model{"a": {ID: 1, CreatedAt: local}}, rfc3339)
+	})
+	assertOk(t, "DiscardsPrecision", func(t testing.TB) {
+		Equal(t, utc, local.Add(time.Millisecond), rfc3339)
+	})
+	assertOk(t, "Struct", func(t testing.TB) {
+		type point struct{ X, Y int }
+		Equal(t, []point{{1, 2}}, []point{{2, 1}}, Transform("sum", func(p point) int { return p.X + p.Y }))
+	})
+	assertFail(t, "Different", func(t testing.TB) {
+		Equal(t, &Model{ID: 1, CreatedAt: utc}, &Model{ID: 1, CreatedAt: local.Add(time.Second)}, rfc3339)
+	})
+	tester := &testTester{T: t}
+	NotEqual(tester, utc, local, rfc3339)
+	Contains(t, tester.failed, `Transform("UTC") compares values of type time.Time by their transformed value`)
+}
+
+func TestDeepDiffPaths(t *testing.T) {
+	type user struct {
+		Name  string
+		Email string
+	}
+	type users struct {
+		Users  []user
+		Labels map[string]int
+		Next   *users
+		Any    any
+	}
+	expected := users{Users: []user{{"a", "a@x"}, {"b", "b@x"}}, Labels: map[string]int{"a": 1}, Next: &users{}, Any: 1}
+	actual := users{Users: []user{{"a", "a@x"}, {"b", "b@y"}}, Labels: map[string]int{"a": 2}, Next: &users{Any: "x"}, Any: "1"}
+	diffs := expandCompareOptions().deepDiff(expected, actual, 10)
+	Equal(t, []string{".Users[1].Email", `.Labels["a"]`, ".Next.Any", ".Any"}, diffs)
+	Equal(t, []string{".Users[1].Email"}, expandCompareOptions().deepDiff(expected, actual, 1))
+	Equal(t, []string{"."}, expandCompareOptions().deepDiff(1, 2, 10))
+	Equal(t, []string(nil), expandCompareOptions().deepDiff(expected, expected, 10))
+}
+
+func TestApproxFloat(t *testing.T) {
+	type Point struct {
+		X, Y float64
+	}
+	type Celsius float32
+	assertOk(t, "TopLevel", func(t testing.TB) {
+		Equal(t, 0.3, 0.1+0.2, ApproxFloat(1e-9))
+	})
+	assertOk(t, "Nested", func(t testing.TB) {
+		Equal(t, []Point{{X: 1, Y: 2}}, []Point{{X: 1.0000001, Y: 1.9999999}}, ApproxFloat(1e-6))
+	})
+	assertOk(t, "NamedType", func(t testing.TB) {
+		Equal(t, map[string]Celsius{"a": 20}, map[string]Celsius{"a": 20.01}, ApproxFloat(0.1))
+	})
+	assertOk(t, "NaN", func(t testing.TB) {
+		Equal(t, math.NaN(), math.NaN(), ApproxFloat(1e-9))
+	})
+	assertOk(t, "NilAndEmptyField", func(t testing.TB) {
+		type Shape struct {
+			Points []Point
+		}
+		Equal(t, Shape{}, Shape{Points: []Point{}}, ApproxFloat(1e-9))
+	})
+	assertFail(t, "OutsideDelta", func(t testing.TB) {
+		Equal(t, []Point{{X: 1, Y: 2}}, []Point{{X: 1.1, Y: 2}}, ApproxFloat(1e-6))
+	})
+	assertFail(t, "Infinity", func(t testing.TB) {
+		Equal(t, math.Inf(1), math.MaxFloat64, ApproxFloat(math.MaxFloat64))
+	})
+}
+
+func TestDeepCompare(t *testing.T) {
+	type Item struct {
+		Name    string
+		Handler func()
+		Secret  string
+	}
+	type Order struct {
+		Items []Item
+	}
+	handler := func() {}
+	assertOk(t, "Equal", func(t testing.TB) {
+		Equal(t, Order{Items: []Item{{Name: "a", Handler: handler}}}, Order{Items: []Item{{Name: "a", Handler: handler}}}, DeepCompare())
+	})
+	assertOk(t, "Exclude", func(t testing.TB) {
+		Equal(t, Order{Items: []Item{{Name: "a", Secret: "x"}}}, Order{Items: []Item{{Name: "a", Secret: "y"}}}, DeepCompare(), ExcludeFields("Secret"))
+	})
+	assertFail(t, "Func", func(t testing.TB) {
+		Equal(t, Item{Name: "a", Handler: handler}, Item{Name: "a", Handler: func() {}}, DeepCompare())
+	})
+	assertOk(t, "OmitEmptyByDefault", func(t testing.TB) {
+		Equal(t, Order{Items: []Item{}}, Order{}, DeepCompare())
+	})
+	assertOk(t, "FuncWithoutDeepCompare", func(t testing.TB) {
+		Equal(t, Item{Name: "a", Handler: handler}, Item{Name: "a", Handler: func() {}})
+	})
+	tester := &testTester{T: t}
+	expected := Order{Items: []Item{{Name: "a"}, {Name: "b"}, {Name: "c"}}}
+	actual := Order{Items: []Item{{Name: "a"}, {Name: "B"}, {Name: "C"}}}
+	Equal(tester, expected, actual, DeepCompare(), DiffContext(0))
+	Equal(t, `Expected values to be equal:
+Difference at .Items[1].Name
+Difference at .Items[2].Name
+-      Name: "b",
++      Name: "B",
+@@ -10 +10 @@
+-      Name: "c",
++      Name: "C",
+`, tester.failed)
+}
+
+func TestDiffPaths(t *testing.T) {
+	type User struct {
+		Name  string
+		Email string
+	}
+	expected := []User{{"a", "a@example.com"}, {"b", "b@example.com"}, {"c", "c@example.com"}}
+	actual := []User{{"a", "a@example.com"}, {"b", "b@example.org"}, {"c", "c@example.org"}}
+	tester := &testTester{T: t}
+	Equal(tester, expected, actual, DiffPaths(1), DiffContext(0))
+	HasPrefix(t, tester.failed, "Expected values to be equal:\nDifference at [1].Email\n-")
+	tester = &testTester{T: t}
+	Equal(tester, expected, actual, DiffPaths(5), DiffContext(0))
+	HasPrefix(t, tester.failed, "Expected values to be equal:\nDifference at [1].Email\nDifference at [2].Email\n-")
+	tester = &testTester{T: t}
+	Equal(tester, "a", "b", DiffPaths(5))
+	Equal(t, "Expected values to be equal:\nExpected: \"a\"\nActual:   \"b\"\n           ^\n", tester.failed)
+}
@@ -0,0 +1,537 @@
+package assert
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"unicode/utf8"
+
+	"github.com/alecthomas/repr"
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+)
+
+// DefaultDiffContext is the number of unchanged lines shown around each change in a diff,
+// unless overridden by the DiffContext option. Negative values are treated as zero.
+var DefaultDiffContext = 3
+
+// DefaultMaxDiffLines is the maximum number of lines in a diff before it is truncated,
+// unless overridden by the MaxDiffLines option. Zero or a negative value means diffs are
+// never truncated.
+var DefaultMaxDiffLines = 0
+
+// color is non-zero if diffs in failure messages are colored. See SetColor.
+var color = func() int32 {
+	if colorByDefault() {
+		return 1
+	}
+	return 0
+}()
+
+// SetColor controls whether diffs in failure messages are colored with ANSI escape
+// sequences, and returns a function that restores the previous setting.
+//
+// Color defaults to true if stderr is a terminal, unless the NO_COLOR or CI environment
+// variables are set. The diffs returned by Diff and CompareDiff are never colored.
+func SetColor(enabled bool) (restore func()) {
+	var value int32
+	if enabled {
+		value = 1
+	}
+	previous := atomic.SwapInt32(&color, value)
+	return func() { atomic.StoreInt32(&color, previous) }
+}
+
+const (
+	ansiRed   = "\x1b[31m"
+	ansiGreen = "\x1b[32m"
+	ansiReset = "\x1b[0m"
+)
+
+func colorByDefault() bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("CI") != "" {
+		return false
+	}
+	info, err := os.Stderr.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// colorDiff colors the inserted and deleted lines of a diff for a failure message, if
+// enabled with SetColor.
+func colorDiff(diff string) string {
+	if atomic.LoadInt32(&color) == 0 {
+		return diff
+	}
+	w := &strings.Builder{}
+	for _, line := range strings.SplitAfter(diff, "\n") {
+		content := strings.TrimSuffix(line, "\n")
+		switch {
+		case strings.HasPrefix(content, "-"):
+			w.WriteString(ansiRed + content + ansiReset + line[len(content):])
+		case strings.HasPrefix(content, "+"):
+			w.WriteString(ansiGreen + content + ansiReset + line[len(content):])
+		default:
+			w.WriteString(line)
+		}
+	}
+	return w.String()
+}
+
+var (
+	differsLock sync.RWMutex
+	differs     = map[reflect.Type]func(a, b any) string{}
+)
+
+// RegisterDiffer registers "differ" to render the difference between two values of type T
+// in place of the usual diff, and returns a function that restores the previous differ for
+// T, eg.
+//
+//	assert.RegisterDiffer(func(a, b time.Time) string {
+//		return fmt.Sprintf("Expected: %s\nActual:   %s\nDelta:    %s\n", a, b, b.Sub(a))
+//	})
+//
+// The differ is used by Diff, and so by Equal and friends, when the values being diffed
+// are both of type T. Values of type T nested within other values are diffed as usual,
+// as part of their enclosing values. If T is an interface type, a nil value is passed to the
+// differ as the zero T.
+//
+// The differ's output is used verbatim, so compare options that affect diffs, such as
+// DiffContext, MaxDiffLines and FirstDiffOnly, and normalising options such as IgnoreCase,
+// do not apply to it.
+func RegisterDiffer[T any](differ func(a, b T) string) (restore func()) {
+	typ := typeOf[T]()
+	differsLock.Lock()
+	defer differsLock.Unlock()
+	previous, ok := differs[typ]
+	differs[typ] = func(a, b any) string { return differ(as[T](a), as[T](b)) }
+	return func() {
+		differsLock.Lock()
+		defer differsLock.Unlock()
+		if ok {
+			differs[typ] = previous
+		} else {
+			delete(differs, typ)
+		}
+	}
+}
+
+// registeredDiff returns the output of the differ registered for the type of "before" and
+// "after", if any.
+func registeredDiff[T any](before, after T) (string, bool) {
+	lhs, rhs := any(before), any(after)
+	differsLock.RLock()
+	differ, ok := differs[typeOf[T]()]
+	if !ok && lhs != nil && rhs != nil && reflect.TypeOf(lhs) == reflect.TypeOf(rhs) {
+		// T may be an interface, so also look up the dynamic type of the values.
+		differ, ok = differs[reflect.TypeOf(lhs)]
+	}
+	differsLock.RUnlock()
+	if !ok {
+		return "", false
+	}
+	return differ(lhs, rhs), true
+}
+
+// DiffContext sets the number of unchanged lines shown around each change in a diff.
+//
+// Negative values are treated as zero.
+func DiffContext(lines int) CompareOption {
+	return func(o *compareOptions) {
+		o.diffContext = lines
+		o.diffContextSet = true
+	}
+}
+
+// MaxDiffLines truncates diffs longer than "lines", omitting whole hunks where possible.
+// Zero or a negative value means diffs are never truncated.
+func MaxDiffLines(lines int) CompareOption {
+	return func(o *compareOptions) {
+		o.maxDiffLines = lines
+		o.maxDiffLinesSet = true
+	}
+}
+
+// FirstDiffOnly limits diffs to their first hunk, followed by a note of how many more hunks
+// were omitted.
+//
+// This gives quick feedback when only the first divergence matters, eg. in large table
+// driven tests. Use SetDefaultCompareOptions to enable it for every comparison.
+func FirstDiffOnly() CompareOption {
+	return func(o *compareOptions) {
+		o.firstDiffOnly = true
+	}
+}
+
+// NumberFormat controls how numbers within values are rendered in diffs.
+//
+// If "separator" is not empty it is used to group the integer digits of numbers into
+// thousands, and if "precision" is not negative floating point numbers are rendered with
+// that many digits after the decimal point. Arguments of constructor calls such as
+// time.Date(...) are not formatted. This only affects rendering, not equality, so values
+// differing beyond "precision" may render identically.
+func NumberFormat(separator string, precision int) CompareOption {
+	return func(o *compareOptions) {
+		o.numberFormat = &numberFormat{separator: separator, precision: precision}
+	}
+}
+
+type numberFormat struct {
+	separator string
+	precision int
+}
+
+// format the numeric literals of leaf values in "s", the repr of a value, leaving string and
+// rune literals and identifiers untouched.
+//
+// Numbers are only formatted where they are values in their own right, optionally converted
+// to a type as in uint8(255). Arguments of constructor calls such as time.Date(...) and array
+// lengths are left as they are, so that the result remains valid Go.
+func (n *numberFormat) format(s string) string {
+	w := &strings.Builder{}
+	for i := 0; i < len(s); {
+		switch ch := s[i]; {
+		case ch == '"' || ch == '\'' || ch == '`':
+			end := quotedEnd(s, i)
+			w.WriteString(s[i:end])
+			i = end
+
+		case ch == '(':
+			end := bracketEnd(s, i)
+			inner := s[i+1 : end-1]
+			if number := strings.TrimPrefix(inner, "-"); s[end-1] == ')' && isNumber(number) {
+				w.WriteString("(" + inner[:len(inner)-len(number)] + n.formatNumber(number) + ")")
+			} else {
+				w.WriteString(s[i:end])
+			}
+			i = end
+
+		case ch == '[':
+			end := bracketEnd(s, i)
+			w.WriteString(s[i:end])
+			i = end
+
+		case ch >= '0' && ch <= '9':
+			end := numberEnd(s, i)
+			w.WriteString(n.formatNumber(s[i:end]))
+			i = end
+
+		case isIdentChar(ch):
+			end := i
+			for end < len(s) && isIdentChar(s[end]) {
+				end++
+			}
+			w.WriteString(s[i:end])
+			i = end
+
+		default:
+			w.WriteByte(ch)
+			i++
+		}
+	}
+	return w.String()
+}
+
+// quotedEnd returns the index after the string or rune literal starting at s[i].
+func quotedEnd(s string, i int) int {
+	quote := s[i]
+	end := i + 1
+	for end < len(s) && s[end] != quote {
+		if s[end] == '\\' && quote != '`' {
+			end++
+		}
+		end++
+	}
+	end++
+	if end > len(s) {
+		end = len(s)
+	}
+	return end
+}
+
+// bracketEnd returns the index after the bracket matching the one at s[i], or len(s) if it
+// is unmatched.
+func bracketEnd(s string, i int) int {
+	depth := 0
+	for end := i; end < len(s); {
+		switch s[end] {
+		case '"', '\'', '`':
+			end = quotedEnd(s, end)
+			continue
+		case '(', '[', '{':
+			depth++
+		case ')', ']', '}':
+			depth--
+			if depth == 0 {
+				return end + 1
+			}
+		}
+		end++
+	}
+	return len(s)
+}
+
+// isNumber returns true if "s" is a single numeric literal.
+func isNumber(s string) bool {
+	return s != "" && s[0] >= '0' && s[0] <= '9' && numberEnd(s, 0) == len(s)
+}
+
+// numberEnd returns the index after the numeric literal starting at s[i].
+func numberEnd(s string, i int) int {
+	end := i
+	for end < len(s) && (isIdentChar(s[end]) || s[end] == '.' || (s[end] == '+' || s[end] == '-') && end > i && (s[end-1] == 'e' || s[end-1] == 'E')) {
+		end++
+	}
+	return end
+}
+
+func (n *numberFormat) formatNumber(token string) string {
+	isFloat := strings.ContainsAny(token, ".eE")
+	if !isFloat {
+		if _, err := strconv.ParseUint(token, 10, 64); err != nil {
+			return token // Eg. a hex literal.
+		}
+		return n.group(token)
+	}
+	f, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return token
+	}
+	if n.precision >= 0 {
+		token = strconv.FormatFloat(f, 'f', n.precision, 64)
+	} else if strings.ContainsAny(token, "eE") {
+		return token
+	}
+	integer, fraction := token, ""
+	if dot := strings.IndexByte(token, '.'); dot >= 0 {
+		integer, fraction = token[:dot], token[dot:]
+	}
+	return n.group(integer) + fraction
+}
+
+// group the digits of an integer into thousands.
+func (n *numberFormat) group(digits string) string {
+	if n.separator == "" || len(digits) <= 3 {
+		return digits
+	}
+	w := &strings.Builder{}
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			w.WriteString(n.separator)
+		}
+		w.WriteRune(digit)
+	}
+	return w.String()
+}
+
+func isIdentChar(ch byte) bool {
+	return ch == '_' || ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9'
+}
+
+// inlineDiff returns two single-line strings quoted one above the other, with a marker
+// beneath the first differing character.
+func inlineDiff(before, after string) string {
+	i := 0
+	for i < len(before) && i < len(after) && before[i] == after[i] {
+		i++
+	}
+	// Don't split a multi-byte rune.
+	for i > 0 && (i < len(before) && !utf8.RuneStart(before[i]) || i < len(after) && !utf8.RuneStart(after[i])) {
+		i--
+	}
+	offset := utf8.RuneCountInString(strconv.Quote(before[:i])) - 1
+	return fmt.Sprintf("Expected: %s\nActual:   %s\n          %s^\n", strconv.Quote(before), strconv.Quote(after), strings.Repeat(" ", offset))
+}
+
+// unifiedDiff returns a unified diff of two strings, omitting the file and first hunk headers.
+func (o *compareOptions) unifiedDiff(before, after string) string {
+	edits := myers.ComputeEdits("a.txt", before, after)
+	lines := diffLines(before, gotextdiff.ToUnified("expected.txt", "actual.txt", before, edits))
+	context := DefaultDiffContext
+	if o.diffContextSet {
+		context = o.diffContext
+	}
+	maxLines := DefaultMaxDiffLines
+	if o.maxDiffLinesSet {
+		maxLines = o.maxDiffLines
+	}
+	w := &strings.Builder{}
+	written, omitted, omittedHunks := 0, 0, 0
+	for i, hunk := range diffHunks(lines, context) {
+		if o.firstDiffOnly && i > 0 {
+			omittedHunks++
+			continue
+		}
+		hw := &strings.Builder{}
+		if i > 0 {
+			hunk.writeHeader(hw)
+		}
+		hunk.writeLines(hw)
+		out := hw.String()
+		count := strings.Count(out, "\n")
+		switch {
+		case maxLines <= 0 || (omitted == 0 && written+count <= maxLines):
+			w.WriteString(out)
+			written += count
+		case written == 0:
+			// The first hunk alone exceeds the limit, so truncate it.
+			keep := strings.SplitAfterN(out, "\n", maxLines+1)[:maxLines]
+			w.WriteString(strings.Join(keep, ""))
+			written += maxLines
+			omitted += count - maxLines
+		default:
+			omitted += count
+		}
+	}
+	if omitted > 0 {
+		fmt.Fprintf(w, "... (%d more lines omitted)\n", omitted)
+	}
+	if omittedHunks > 0 {
+		fmt.Fprintf(w, "... (%d more hunks omitted, remove FirstDiffOnly to show them)\n", omittedHunks)
+	}
+	return w.String()
+}
+
+// diffSize returns the number of lines inserted or deleted by a diff of two values.
+func diffSize(before, after any) int {
+	lhs := repr.String(before, repr.Indent("  ")) + "\n"
+	rhs := repr.String(after, repr.Indent("  ")) + "\n"
+	size := 0
+	for _, hunk := range gotextdiff.ToUnified("", "", lhs, myers.ComputeEdits("a.txt", lhs, rhs)).Hunks {
+		for _, line := range hunk.Lines {
+			if line.Kind != gotextdiff.Equal {
+				size++
+			}
+		}
+	}
+	return size
+}
+
+// diffLines expands a unified diff into every line of the diff, including all unchanged lines.
+func diffLines(before string, unified gotextdiff.Unified) []gotextdiff.Line {
+	original := strings.SplitAfter(before, "\n")
+	if original[len(original)-1] == "" {
+		original = original[:len(original)-1]
+	}
+	lines := []gotextdiff.Line{}
+	cursor := 0
+	for _, hunk := range unified.Hunks {
+		for ; cursor < hunk.FromLine-1; cursor++ {
+			lines = append(lines, gotextdiff.Line{Kind: gotextdiff.Equal, Content: original[cursor]})
+		}
+		for _, line := range hunk.Lines {
+			lines = append(lines, line)
+			if line.Kind != gotextdiff.Insert {
+				cursor++
+			}
+		}
+	}
+	if len(unified.Hunks) == 0 {
+		return nil
+	}
+	for ; cursor < len(original); cursor++ {
+		lines = append(lines, gotextdiff.Line{Kind: gotextdiff.Equal, Content: original[cursor]})
+	}
+	return lines
+}
+
+type diffHunk struct {
+	fromLine, toLine int
+	lines            []gotextdiff.Line
+}
+
+func (h *diffHunk) writeHeader(w *strings.Builder) {
+	fromCount, toCount := 0, 0
+	for _, line := range h.lines {
+		switch line.Kind {
+		case gotextdiff.Delete:
+			fromCount++
+		case gotextdiff.Insert:
+			toCount++
+		default:
+			fromCount++
+			toCount++
+		}
+	}
+	fmt.Fprint(w, "@@")
+	if fromCount > 1 {
+		fmt.Fprintf(w, " -%d,%d", h.fromLine, fromCount)
+	} else {
+		fmt.Fprintf(w, " -%d", h.fromLine)
+	}
+	if toCount > 1 {
+		fmt.Fprintf(w, " +%d,%d", h.toLine, toCount)
+	} else {
+		fmt.Fprintf(w, " +%d", h.toLine)
+	}
+	fmt.Fprint(w, " @@\n")
+}
+
+// writeLines writes the lines of the hunk, without its header.
+func (h *diffHunk) writeLines(w *strings.Builder) {
+	for _, line := range h.lines {
+		content := strings.TrimSuffix(line.Content, "\n")
+		switch {
+		case line.Kind == gotextdiff.Delete:
+			fmt.Fprintf(w, "-%s\n", content)
+		case line.Kind == gotextdiff.Insert:
+			fmt.Fprintf(w, "+%s\n", content)
+		default:
+			fmt.Fprintf(w, " %s\n", content)
+		}
+		if !strings.HasSuffix(line.Content, "\n") {
+			fmt.Fprintf(w, "\\ No newline at end of file\n")
+		}
+	}
+}
+
+// diffHunks groups changed lines into hunks with "context" unchanged lines around each change.
+//
+// Changes separated by no more than twice the context are merged into a single hunk. A
+// negative context is treated as zero.
+func diffHunks(lines []gotextdiff.Line, context int) []*diffHunk {
+	if context < 0 {
+		context = 0
+	}
+	hunks := []*diffHunk{}
+	var hunk *diffHunk
+	fromLine, toLine := 1, 1
+	lastChange := -1
+	for i, line := range lines {
+		if line.Kind != gotextdiff.Equal {
+			if hunk == nil || i-lastChange-1 > 2*context {
+				if hunk != nil {
+					hunk.lines = append(hunk.lines, lines[lastChange+1:lastChange+1+context]...)
+				}
+				start := i - context
+				if start < lastChange+1 {
+					start = lastChange + 1
+				}
+				hunk = &diffHunk{fromLine: fromLine - (i - start), toLine: toLine - (i - start)}
+				hunks = append(hunks, hunk)
+				hunk.lines = append(hunk.lines, lines[start:i]...)
+			} else {
+				hunk.lines = append(hunk.lines, lines[lastChange+1:i]...)
+			}
+			hunk.lines = append(hunk.lines, line)
+			lastChange = i
+		}
+		if line.Kind != gotextdiff.Insert {
+			fromLine++
+		}
+		if line.Kind != gotextdiff.Delete {
+			toLine++
+		}
+	}
+	if hunk != nil {
+		end := lastChange + 1 + context
+		if end > len(lines) {
+			end = len(lines)
+		}
+		hunk.lines = append(hunk.lines, lines[lastChange+1:end]...)
+	}
+	return hunks
+}
@@ -0,0 +1,157 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func numberedLines(n int, changed map[int]string) string {
+	lines := []string{}
+	for i := 1; i <= n; i++ {
+		if line, ok := changed[i]; ok {
+			lines = append(lines, line)
+		} else {
+			lines = append(lines, strconv.Itoa(i))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestDiffContext(t *testing.T) {
+	before := numberedLines(20, map[int]string{10: "before"})
+	after := numberedLines(20, map[int]string{10: "after"})
+	Equal(t, " 7\n 8\n 9\n-before\n+after\n 11\n 12\n 13\n", Diff(before, after))
+	Equal(t, " 9\n-before\n+after\n 11\n", Diff(before, after, DiffContext(1)))
+	Equal(t, "-before\n+after\n", Diff(before, after, DiffContext(0)))
+	Equal(t, "-before\n+after\n", Diff(before, after, DiffContext(-1)))
+}
+
+func TestDiffContextHunks(t *testing.T) {
+	before := numberedLines(20, map[int]string{5: "a", 15: "b"})
+	after := numberedLines(20, map[int]string{5: "A", 15: "B"})
+	Equal(t, " 4\n-a\n+A\n 6\n@@ -14,3 +14,3 @@\n 14\n-b\n+B\n 16\n", Diff(before, after, DiffContext(1)))
+	Equal(t, 0, strings.Count(Diff(before, after, DiffContext(5)), "@@"))
+}
+
+func TestMaxDiffLines(t *testing.T) {
+	before := numberedLines(20, map[int]string{5: "a", 15: "b"})
+	after := numberedLines(20, map[int]string{5: "A", 15: "B"})
+	full := " 4\n-a\n+A\n 6\n@@ -14,3 +14,3 @@\n 14\n-b\n+B\n 16\n"
+	Equal(t, full, Diff(before, after, DiffContext(1), MaxDiffLines(9)))
+	Equal(t, full, Diff(before, after, DiffContext(1), MaxDiffLines(0)))
+	Equal(t, full, Diff(before, after, DiffContext(1), MaxDiffLines(-1)))
+	Equal(t, " 4\n-a\n+A\n 6\n... (5 more lines omitted)\n", Diff(before, after, DiffContext(1), MaxDiffLines(8)))
+	Equal(t, " 4\n-a\n... (7 more lines omitted)\n", Diff(before, after, DiffContext(1), MaxDiffLines(2)))
+}
+
+func TestFirstDiffOnly(t *testing.T) {
+	before := numberedLines(30, map[int]string{5: "a", 15: "b", 25: "c"})
+	after := numberedLines(30, map[int]string{5: "A", 15: "B", 25: "C"})
+	Equal(t, " 4\n-a\n+A\n 6\n... (2 more hunks omitted, remove FirstDiffOnly to show them)\n", Diff(before, after, DiffContext(1), FirstDiffOnly()))
+	Equal(t, " 4\n-a\n... (2 more lines omitted)\n... (2 more hunks omitted, remove FirstDiffOnly to show them)\n", Diff(before, after, DiffContext(1), FirstDiffOnly(), MaxDiffLines(2)))
+	single := numberedLines(30, map[int]string{5: "a"})
+	Equal(t, " 4\n-a\n+5\n 6\n", Diff(single, numberedLines(30, nil), DiffContext(1), FirstDiffOnly()))
+}
+
+func TestDefaultMaxDiffLines(t *testing.T) {
+	defer func(lines int) { DefaultMaxDiffLines = lines }(DefaultMaxDiffLines)
+	DefaultMaxDiffLines = 2
+	before := numberedLines(20, map[int]string{10: "before"})
+	after := numberedLines(20, map[int]string{10: "after"})
+	Equal(t, " 7\n 8\n... (6 more lines omitted)\n", Diff(before, after))
+	Equal(t, " 7\n 8\n 9\n-before\n+after\n 11\n 12\n 13\n", Diff(before, after, MaxDiffLines(0)))
+}
+
+func TestRegisterDiffer(t *testing.T) {
+	type Celsius float64
+	type Reading struct {
+		Temperature Celsius
+	}
+	restore := RegisterDiffer(func(a, b Celsius) string {
+		return fmt.Sprintf("%.1f°C -> %.1f°C\n", a, b)
+	})
+	Equal(t, "20.0°C -> 21.5°C\n", Diff(Celsius(20), Celsius(21.5)))
+	Equal(t, "20.0°C -> 21.5°C\n", Diff[any](Celsius(20), Celsius(21.5)))
+	Equal(t, " assert.Reading{\n-  Temperature: assert.Celsius(20),\n+  Temperature: assert.Celsius(21.5),\n }\n", Diff(Reading{20}, Reading{21.5}))
+	tester := &testTester{T: t}
+	Equal(tester, Celsius(20), Celsius(21.5))
+	Equal(t, "Expected values to be equal:\n20.0°C -> 21.5°C\n", tester.failed)
+	restore()
+	Equal(t, "-assert.Celsius(20)\n+assert.Celsius(21.5)\n", Diff(Celsius(20), Celsius(21.5)))
+
+	restore = RegisterDiffer(func(a, b error) string { return fmt.Sprintf("%v -> %v\n", a, b) })
+	defer restore()
+	Equal(t, "<nil> -> failed\n", Diff[error](nil, errors.New("failed")))
+}
+
+func TestDefaultDiffContext(t *testing.T) {
+	defer func(context int) { DefaultDiffContext = context }(DefaultDiffContext)
+	DefaultDiffContext = 0
+	before := numberedLines(20, map[int]string{10: "before"})
+	after := numberedLines(20, map[int]string{10: "after"})
+	Equal(t, "-before\n+after\n", Diff(before, after))
+	Equal(t, " 9\n-before\n+after\n 11\n", Diff(before, after, DiffContext(1)))
+	DefaultDiffContext = -1
+	Equal(t, "-before\n+after\n", Diff(before, after))
+}
+
+func TestDiffColor(t *testing.T) {
+	restore := SetColor(true)
+	defer restore()
+	Equal(t, "-before\n+after\n \n", Diff("before\n", "after\n"), "Diff is never colored")
+	tester := &testTester{T: t}
+	var failure Failure
+	defer OnFailure(func(f Failure) { failure = f })()
+	Equal(tester, "before\n", "after\n")
+	Equal(t, "Expected values to be equal:\n\x1b[31m-before\x1b[0m\n\x1b[32m+after\x1b[0m\n \n", tester.failed)
+	Equal(t, "-before\n+after\n \n", failure.Diff)
+	SetColor(false)
+	Equal(tester, "before\n", "after\n")
+	Equal(t, "Expected values to be equal:\n-before\n+after\n \n", tester.failed)
+}
+
+func TestNumberFormat(t *testing.T) {
+	Equal(t, "-1,000,000\n+1,000,001\n", Diff(1000000, 1000001, NumberFormat(",", -1)))
+	Equal(t, "-1234.57\n+1234.90\n", Diff(1234.5678, 1234.9, NumberFormat("", 2)))
+	type Account struct {
+		Name    string
+		Balance float64
+	}
+	before := Account{Name: "12345", Balance: 1234567.891}
+	after := Account{Name: "12345", Balance: 1234567.5}
+	Equal(t, " assert.Account{\n   Name: \"12345\",\n-  Balance: 1_234_567.9,\n+  Balance: 1_234_567.5,\n }\n",
+		Diff(before, after, NumberFormat("_", 1)))
+	Equal(t, "Expected: \"1000000\"\nActual:   \"1000001\"\n                 ^\n", Diff("1000000", "1000001", NumberFormat(",", -1)))
+}
+
+func TestNumberFormatLiterals(t *testing.T) {
+	n := &numberFormat{separator: ",", precision: -1}
+	Equal(t, `T2{A: 1,234, B: "1234", C: 'x', D: 0xc000012345, E: uint8(255), F: -1,000.5, G: 1e+21}`,
+		n.format(`T2{A: 1234, B: "1234", C: 'x', D: 0xc000012345, E: uint8(255), F: -1000.5, G: 1e+21}`))
+	Equal(t, `T3{A: int64(1,000), B: float64(-1,234.5), C: [1000]int{1,000}, D: time.Date(2024, time.January, 2, 3, 4, 5, 1000, time.UTC), E: (*int)(nil), F: f("1000", 1000)}`,
+		n.format(`T3{A: int64(1000), B: float64(-1234.5), C: [1000]int{1000}, D: time.Date(2024, time.January, 2, 3, 4, 5, 1000, time.UTC), E: (*int)(nil), F: f("1000", 1000)}`))
+}
+
+func TestNumberFormatTime(t *testing.T) {
+	type Event struct {
+		ID int
+		At time.Time
+	}
+	before := Event{ID: 1000, At: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)}
+	after := Event{ID: 2000, At: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC)}
+	Equal(t, " assert.Event{\n-  ID: 1,000,\n+  ID: 2,000,\n   At: time.Date(2024, time.January, 2, 3, 4, 5, 0, time.UTC),\n }\n",
+		Diff(before, after, NumberFormat(",", -1)))
+}
+
+func TestInlineDiff(t *testing.T) {
+	Equal(t, "Expected: \"hello world\"\nActual:   \"hello wurld\"\n                  ^\n", Diff("hello world", "hello wurld"))
+	Equal(t, "Expected: \"abc\"\nActual:   \"abcd\"\n              ^\n", Diff("abc", "abcd"))
+	Equal(t, "Expected: \"a\\tb\"\nActual:   \"a\\tc\"\n              ^\n", Diff("a\tb", "a\tc"))
+	Equal(t, "Expected: \"caf\u00e9!\"\nActual:   \"caf\u00e8!\"\n              ^\n", Diff("caf\u00e9!", "caf\u00e8!"))
+	Equal(t, "", Diff("same", "same"))
+	Equal(t, "-a\n+b\n c\n", Diff("a\nc", "b\nc"))
+}
@@ -0,0 +1,174 @@
+package assert
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/alecthomas/repr"
+)
+
+// Eventually asserts that "condition" returns true within "waitFor", checking every "tick".
+//
+// The condition is called in its own goroutine, so a slow condition can not delay the timeout.
+func Eventually(t testing.TB, condition func() bool, waitFor time.Duration, tick time.Duration, msgAndArgs ...any) {
+	if _, ok := poll(condition, waitFor, tick); ok {
+		return
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Condition never satisfied within %s", waitFor), msgAndArgs...))
+}
+
+// EventuallyCtx asserts that "condition" returns true before "ctx" is done, checking every
+// "tick".
+//
+// The condition is called in its own goroutine with "ctx", so it can observe cancellation.
+// The failure message reports whether the condition was still running when "ctx" was done.
+func EventuallyCtx(ctx context.Context, t testing.TB, condition func(context.Context) bool, tick time.Duration, msgAndArgs ...any) {
+	elapsed, running, ok := pollContext(ctx, condition, tick)
+	if ok {
+		return
+	}
+	t.Helper()
+	elapsed = elapsed.Round(time.Millisecond)
+	if running {
+		fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Condition still running when context was done after %s: %s", elapsed, ctx.Err()), msgAndArgs...))
+		return
+	}
+	fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Condition never satisfied before context was done after %s: %s", elapsed, ctx.Err()), msgAndArgs...))
+}
+
+// Never asserts that "condition" does not return true within "waitFor", checking every "tick".
+func Never(t testing.TB, condition func() bool, waitFor time.Duration, tick time.Duration, msgAndArgs ...any) {
+	elapsed, ok := poll(condition, waitFor, tick)
+	if !ok {
+		return
+	}
+	t.Helper()
+	fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Condition satisfied after %s", elapsed.Round(time.Millisecond)), msgAndArgs...))
+}
+
+// CompletesWithin asserts that "fn" returns within "timeout".
+//
+// The function is called in its own goroutine, so it must not call t.FailNow or the
+// assertions in this package. On timeout the stacks of all goroutines are reported to help
+// debug hangs, and the goroutine running "fn" is leaked if it never returns. If "fn" panics
+// the panic is propagated to the caller.
+func CompletesWithin(t testing.TB, timeout time.Duration, fn func(), msgAndArgs ...any) {
+	done := make(chan struct{})
+	var (
+		panicked bool
+		value    any
+	)
+	go func() {
+		defer close(done)
+		panicked, value = recoverPanic(fn)
+	}()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-done:
+		if panicked {
+			panic(value)
+		}
+
+	case <-timer.C:
+		t.Helper()
+		msg := formatMsgAndArgs(fmt.Sprintf("Function did not complete within %s", timeout), msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\nGoroutines:\n%s", msg, goroutineStacks())
+	}
+}
+
+// goroutineStacks returns the stacks of all goroutines.
+func goroutineStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+// Receives asserts that a value is received from "ch" within "timeout", and returns it.
+func Receives[T any](t testing.TB, ch <-chan T, timeout time.Duration, msgAndArgs ...any) T {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case value, ok := <-ch:
+		if ok {
+			return value
+		}
+		t.Helper()
+		fatal(t, msgAndArgs, formatMsgAndArgs("Channel closed without receiving a value", msgAndArgs...))
+
+	case <-timer.C:
+		t.Helper()
+		fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("No value received within %s", timeout), msgAndArgs...))
+	}
+	var zero T
+	return zero
+}
+
+// Closed asserts that "ch" is closed within "timeout".
+func Closed[T any](t testing.TB, ch <-chan T, timeout time.Duration, msgAndArgs ...any) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case value, ok := <-ch:
+		if !ok {
+			return
+		}
+		t.Helper()
+		msg := formatMsgAndArgs("Expected channel to be closed but received:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, repr.String(value, repr.Indent("  ")))
+
+	case <-timer.C:
+		t.Helper()
+		fatal(t, msgAndArgs, formatMsgAndArgs(fmt.Sprintf("Channel not closed within %s", timeout), msgAndArgs...))
+	}
+}
+
+// poll calls "condition" every "tick" until it returns true or "waitFor" elapses.
+//
+// It returns the time elapsed and true if the condition was satisfied.
+func poll(condition func() bool, waitFor time.Duration, tick time.Duration) (time.Duration, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), waitFor)
+	defer cancel()
+	elapsed, _, ok := pollContext(ctx, func(context.Context) bool { return condition() }, tick)
+	return elapsed, ok
+}
+
+// pollContext calls "condition" every "tick" until it returns true or "ctx" is done.
+//
+// It returns the time elapsed, whether the condition was still running when "ctx" was done,
+// and true if the condition was satisfied.
+func pollContext(ctx context.Context, condition func(context.Context) bool, tick time.Duration) (elapsed time.Duration, running, ok bool) {
+	start := time.Now()
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+	// Buffered so that a condition still running after the timeout does not block forever.
+	results := make(chan bool, 1)
+	var pending <-chan bool
+	for {
+		select {
+		case <-ctx.Done():
+			return time.Since(start), pending != nil, false
+
+		case <-ticker.C:
+			if pending == nil {
+				pending = results
+				go func() { results <- condition(ctx) }()
+			}
+
+		case result := <-pending:
+			pending = nil
+			if result {
+				return time.Since(start), false, true
+			}
+		}
+	}
+}
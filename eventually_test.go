@@ -0,0 +1,131 @@
+package assert
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEventually(t *testing.T) {
+	assertOk(t, "Satisfied", func(t testing.TB) {
+		var calls int32
+		Eventually(t, func() bool { return atomic.AddInt32(&calls, 1) == 3 }, time.Second, time.Millisecond)
+	})
+	assertFail(t, "NeverSatisfied", func(t testing.TB) {
+		Eventually(t, func() bool { return false }, 20*time.Millisecond, time.Millisecond)
+	})
+	start := time.Now()
+	assertFail(t, "SlowCondition", func(t testing.TB) {
+		Eventually(t, func() bool { time.Sleep(time.Second); return true }, 20*time.Millisecond, time.Millisecond)
+	})
+	Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestEventuallyCtx(t *testing.T) {
+	assertOk(t, "Satisfied", func(t testing.TB) {
+		var calls int32
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		EventuallyCtx(ctx, t, func(context.Context) bool { return atomic.AddInt32(&calls, 1) == 3 }, time.Millisecond)
+	})
+	assertFail(t, "Cancelled", func(t testing.TB) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		EventuallyCtx(ctx, t, func(context.Context) bool { return true }, time.Hour)
+	})
+
+	tester := &testTester{T: t}
+	// The condition is only called once, well before the deadline, so that it is not running
+	// when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Millisecond)
+	defer cancel()
+	EventuallyCtx(ctx, tester, func(context.Context) bool { return false }, 40*time.Millisecond)
+	HasPrefix(t, tester.failed, "Condition never satisfied before context was done after ")
+	HasSuffix(t, tester.failed, ": context deadline exceeded")
+
+	start := time.Now()
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	block := make(chan struct{})
+	defer close(block)
+	EventuallyCtx(ctx, tester, func(context.Context) bool { <-block; return true }, time.Millisecond)
+	HasPrefix(t, tester.failed, "Condition still running when context was done after ")
+	Less(t, time.Since(start), 500*time.Millisecond)
+}
+
+func TestNever(t *testing.T) {
+	assertOk(t, "NeverSatisfied", func(t testing.TB) {
+		Never(t, func() bool { return false }, 20*time.Millisecond, time.Millisecond)
+	})
+	assertFail(t, "Satisfied", func(t testing.TB) {
+		var calls int32
+		Never(t, func() bool { return atomic.AddInt32(&calls, 1) == 3 }, time.Second, time.Millisecond)
+	})
+}
+
+func TestReceives(t *testing.T) {
+	assertOk(t, "Buffered", func(t testing.TB) {
+		ch := make(chan int, 1)
+		ch <- 42
+		Equal(t, 42, Receives(t, ch, time.Second))
+	})
+	assertOk(t, "Delayed", func(t testing.TB) {
+		ch := make(chan int)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			ch <- 42
+		}()
+		Equal(t, 42, Receives(t, ch, time.Second))
+	})
+	assertFail(t, "Timeout", func(t testing.TB) {
+		Receives(t, make(chan int), 10*time.Millisecond)
+	})
+	assertFail(t, "Closed", func(t testing.TB) {
+		ch := make(chan int)
+		close(ch)
+		Receives(t, ch, time.Second)
+	})
+}
+
+func TestClosed(t *testing.T) {
+	assertOk(t, "Closed", func(t testing.TB) {
+		ch := make(chan int)
+		close(ch)
+		Closed(t, ch, time.Second)
+	})
+	assertOk(t, "Delayed", func(t testing.TB) {
+		ch := make(chan int)
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			close(ch)
+		}()
+		Closed(t, ch, time.Second)
+	})
+	assertFail(t, "Timeout", func(t testing.TB) {
+		Closed(t, make(chan int), 10*time.Millisecond)
+	})
+	assertFail(t, "Value", func(t testing.TB) {
+		ch := make(chan int, 1)
+		ch <- 42
+		Closed(t, ch, time.Second)
+	})
+}
+
+func TestCompletesWithin(t *testing.T) {
+	assertOk(t, "Completes", func(t testing.TB) {
+		CompletesWithin(t, time.Second, func() {})
+	})
+	block := make(chan struct{})
+	defer close(block)
+	assertFail(t, "Blocks", func(t testing.TB) {
+		CompletesWithin(t, 20*time.Millisecond, func() { <-block })
+	})
+	tester := &testTester{T: t}
+	CompletesWithin(tester, 20*time.Millisecond, func() { <-block })
+	HasPrefix(t, tester.failed, "Function did not complete within 20ms\nGoroutines:\ngoroutine ")
+	Contains(t, tester.failed, "TestCompletesWithin")
+	Equal(t, any("boom"), Panics(t, func() {
+		CompletesWithin(t, time.Second, func() { panic("boom") })
+	}))
+}
@@ -0,0 +1,62 @@
+package assert
+
+import (
+	"sync"
+	"testing"
+)
+
+// A Failure describes a failed assertion. See OnFailure.
+type Failure struct {
+	// Message is the complete failure message, as reported to the test.
+	Message string
+	// Expected and Actual are the values that were compared, for assertions that compare
+	// two values, such as Equal. They are nil otherwise.
+	Expected, Actual any
+	// Diff is the diff of Expected and Actual, if one was displayed.
+	Diff string
+	// Location is the file and line of the failed assertion, eg. "foo_test.go:42".
+	Location string
+}
+
+var (
+	failureHookLock sync.RWMutex
+	failureHook     func(Failure)
+)
+
+// OnFailure registers a function that is called with a description of each failed
+// assertion, immediately before the failure is reported to the test. It returns a function
+// that restores the previous hook.
+//
+// This does not change how failures are reported, but allows them to be recorded, eg. in a
+// machine-readable report. Passing nil removes the hook.
+func OnFailure(hook func(Failure)) (restore func()) {
+	failureHookLock.Lock()
+	defer failureHookLock.Unlock()
+	previous := failureHook
+	failureHook = hook
+	return func() {
+		failureHookLock.Lock()
+		defer failureHookLock.Unlock()
+		failureHook = previous
+	}
+}
+
+// reportFailure calls any hook registered with OnFailure, and returns the message to report.
+func reportFailure(msgAndArgs []any, failure Failure) string {
+	failure.Message = appendDumps(failure.Message, msgAndArgs)
+	failureHookLock.RLock()
+	hook := failureHook
+	failureHookLock.RUnlock()
+	if hook != nil {
+		failure.Location = callerLocation()
+		hook(failure)
+	}
+	return failure.Message
+}
+
+// fatalCompare is like fatal, but includes the compared values and their diff in the
+// Failure passed to any OnFailure hook.
+func fatalCompare(t testing.TB, msgAndArgs []any, expected, actual any, diff, msg string) {
+	t.Helper()
+	t.Fatalf("%s", reportFailure(msgAndArgs, Failure{Message: msg, Expected: expected, Actual: actual, Diff: diff}))
+}
@@ -0,0 +1,27 @@
+package assert
+
+import "testing"
+
+func TestOnFailure(t *testing.T) {
+	var failures []Failure
+	restore := OnFailure(func(failure Failure) { failures = append(failures, failure) })
+	tester := &testTester{T: t}
+	Equal(tester, 1, 2)
+	True(tester, false, "custom message", Dump("value", 42))
+	Equal(t, 1, 1)
+	restore()
+	Equal(tester, 1, 2)
+
+	Equal(t, 2, len(failures))
+	Equal(t, Failure{
+		Message:  "Expected values to be equal:\n-1\n+2\n",
+		Expected: 1,
+		Actual:   2,
+		Diff:     "-1\n+2\n",
+		Location: "failure_test.go:9",
+	}, failures[0])
+	Equal(t, Failure{
+		Message:  "custom message\nvalue: 42\n",
+		Location: "failure_test.go:10",
+	}, failures[1])
+}
@@ -0,0 +1,79 @@
+package assert
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+// FileExists asserts that "path" exists and is not a directory.
+func FileExists(t testing.TB, path string, msgAndArgs ...any) {
+	info, err := os.Stat(path)
+	if err == nil && !info.IsDir() {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Expected file to exist:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, err)
+		return
+	}
+	msg := formatMsgAndArgs("Expected a file but found a directory:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPath: %s\n", msg, path)
+}
+
+// DirExists asserts that "path" exists and is a directory.
+func DirExists(t testing.TB, path string, msgAndArgs ...any) {
+	info, err := os.Stat(path)
+	if err == nil && info.IsDir() {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Expected directory to exist:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, err)
+		return
+	}
+	msg := formatMsgAndArgs("Expected a directory but found a file:", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPath: %s\n", msg, path)
+}
+
+// NoFileExists asserts that nothing exists at "path".
+//
+// Errors other than the path not existing, such as permission errors, fail the assertion.
+func NoFileExists(t testing.TB, path string, msgAndArgs ...any) {
+	info, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Could not determine whether file exists:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, err)
+		return
+	}
+	kind := "file"
+	if info.IsDir() {
+		kind = "directory"
+	}
+	msg := formatMsgAndArgs("Expected file to not exist but found a "+kind+":", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPath: %s\n", msg, path)
+}
+
+// FileContains asserts that the contents of the file at "path" contain "needle".
+func FileContains(t testing.TB, path string, needle string, msgAndArgs ...any) {
+	data, err := os.ReadFile(path)
+	if err == nil && strings.Contains(string(data), needle) {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Could not read file:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s", msg, err)
+		return
+	}
+	msg := formatMsgAndArgs("File does not contain needle.", msgAndArgs...)
+	fatalf(t, msgAndArgs, "%s\nPath: %s\nNeedle: %q\nHaystack: %q\n", msg, path, needle, string(data))
+}
@@ -0,0 +1,81 @@
+package assert
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileExists(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	NoError(t, os.WriteFile(file, []byte("hello world"), 0600))
+	assertOk(t, "File", func(t testing.TB) {
+		FileExists(t, file)
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		FileExists(t, filepath.Join(dir, "missing.txt"))
+	})
+	assertFail(t, "Directory", func(t testing.TB) {
+		FileExists(t, dir)
+	})
+}
+
+func TestDirExists(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	NoError(t, os.WriteFile(file, []byte("hello world"), 0600))
+	assertOk(t, "Directory", func(t testing.TB) {
+		DirExists(t, dir)
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		DirExists(t, filepath.Join(dir, "missing"))
+	})
+	assertFail(t, "File", func(t testing.TB) {
+		DirExists(t, file)
+	})
+}
+
+func TestNoFileExists(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	NoError(t, os.WriteFile(file, []byte("hello world"), 0600))
+	assertOk(t, "Missing", func(t testing.TB) {
+		NoFileExists(t, filepath.Join(dir, "missing.txt"))
+	})
+	assertFail(t, "File", func(t testing.TB) {
+		NoFileExists(t, file)
+	})
+	assertFail(t, "Directory", func(t testing.TB) {
+		NoFileExists(t, dir)
+	})
+}
+
+func TestFileContains(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	NoError(t, os.WriteFile(file, []byte("hello world"), 0600))
+	assertOk(t, "Found", func(t testing.TB) {
+		FileContains(t, file, "world")
+	})
+	assertFail(t, "NotFound", func(t testing.TB) {
+		FileContains(t, file, "goodbye")
+	})
+	assertFail(t, "Missing", func(t testing.TB) {
+		FileContains(t, filepath.Join(dir, "missing.txt"), "world")
+	})
+}
+
+func TestFileMessages(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "file.txt")
+	NoError(t, os.WriteFile(file, []byte("hello world"), 0600))
+	tester := &testTester{T: t}
+	FileContains(tester, file, "goodbye")
+	Equal(t, "File does not contain needle.\nPath: "+file+"\nNeedle: \"goodbye\"\nHaystack: \"hello world\"\n", tester.failed)
+	missing := filepath.Join(dir, "missing.txt")
+	FileExists(tester, missing)
+	HasPrefix(t, tester.failed, "Expected file to exist:\nstat "+missing+": ")
+	NoFileExists(tester, dir)
+	Equal(t, "Expected file to not exist but found a directory:\nPath: "+dir+"\n", tester.failed)
+}
@@ -5,4 +5,7 @@ go 1.18
 require (
 	github.com/alecthomas/repr v0.4.0
 	github.com/hexops/gotextdiff v1.0.3
+	golang.org/x/exp v0.0.0-20220722155223-a9213eeb770e
+	google.golang.org/protobuf v1.33.0
+	gopkg.in/yaml.v3 v3.0.1
 )
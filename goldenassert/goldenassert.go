@@ -0,0 +1,118 @@
+// Package goldenassert provides assertions against golden files and snapshots.
+//
+// Importing this package registers -goldenassert.update and -goldenassert.update-snapshots
+// flags with the flag package. The flags are namespaced so that they do not clash with flags
+// defined by the tests themselves, such as the common -update. When the tests are run with
+// -goldenassert.update, golden files are rewritten with the actual values rather than
+// compared, and likewise snapshots with -goldenassert.update-snapshots.
+package goldenassert
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/alecthomas/repr"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alecthomas/assert/v2/internal/message"
+)
+
+const (
+	updateFlag          = "goldenassert.update"
+	updateSnapshotsFlag = "goldenassert.update-snapshots"
+)
+
+var (
+	update          = flag.Bool(updateFlag, false, "update golden files rather than comparing against them")
+	updateSnapshots = flag.Bool(updateSnapshotsFlag, false, "update snapshots rather than comparing against them")
+)
+
+// Equal asserts that "actual" is equal to the contents of the golden file at "goldenPath".
+//
+// If the -goldenassert.update flag is set, the golden file and any missing parent
+// directories are created or overwritten with "actual" instead.
+func Equal(t testing.TB, goldenPath string, actual string, msgAndArgs ...any) {
+	t.Helper()
+	compare(t, "golden file", updateFlag, *update, goldenPath, actual, msgAndArgs...)
+}
+
+// Snapshot asserts that the Go representation of "value" is equal to the snapshot stored for
+// the test in testdata/snapshots/<test name>.snap.
+//
+// The test name is sanitised into a file name, so subtests are stored alongside their parent.
+// Each further snapshot taken by the same test is stored in its own file, numbered from 2.
+//
+// If the -goldenassert.update-snapshots flag is set, the snapshot is created or overwritten
+// instead.
+func Snapshot(t testing.TB, value any, msgAndArgs ...any) {
+	t.Helper()
+	path := filepath.Join("testdata", "snapshots", snapshotName(t)+".snap")
+	compare(t, "snapshot", updateSnapshotsFlag, *updateSnapshots, path, repr.String(value, repr.Indent("  "))+"\n", msgAndArgs...)
+}
+
+var (
+	snapshotsLock sync.Mutex
+	snapshots     = map[testing.TB]int{}
+)
+
+// snapshotName returns the file name, without extension, of the next snapshot taken by "t".
+func snapshotName(t testing.TB) string {
+	snapshotsLock.Lock()
+	defer snapshotsLock.Unlock()
+	count, ok := snapshots[t]
+	if !ok {
+		t.Cleanup(func() {
+			snapshotsLock.Lock()
+			defer snapshotsLock.Unlock()
+			delete(snapshots, t)
+		})
+	}
+	count++
+	snapshots[t] = count
+	name := unsafeFileChars.ReplaceAllString(t.Name(), "_")
+	if count > 1 {
+		name += "." + strconv.Itoa(count)
+	}
+	return name
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// compare asserts that "actual" is equal to the contents of the file at "path", or writes it
+// to the file if "updating" is set.
+func compare(t testing.TB, kind, flagName string, updating bool, path string, actual string, msgAndArgs ...any) {
+	t.Helper()
+	if updating {
+		err := os.MkdirAll(filepath.Dir(path), 0750)
+		if err == nil {
+			err = os.WriteFile(path, []byte(actual), 0600)
+		}
+		if err != nil {
+			assert.FailNow(t, message.Compose(fmt.Sprintf("Could not update %s %s:\n%s", kind, path, err), msgAndArgs)...)
+		}
+		return
+	}
+	expected, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		assert.FailNow(t, message.Compose(fmt.Sprintf("%s %s does not exist, run the tests with -%s to create it", capitalise(kind), path, flagName), msgAndArgs)...)
+		return
+	} else if err != nil {
+		assert.FailNow(t, message.Compose(fmt.Sprintf("Could not read %s %s:\n%s", kind, path, err), msgAndArgs)...)
+		return
+	}
+	msg := fmt.Sprintf("%s %s does not match, run the tests with -%s to update it:", capitalise(kind), path, flagName)
+	assert.Equal(t, string(expected), actual, message.Compose(msg, msgAndArgs)...)
+}
+
+func capitalise(s string) string {
+	return strings.ToUpper(s[:1]) + s[1:]
+}
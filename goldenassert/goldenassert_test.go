@@ -0,0 +1,117 @@
+package goldenassert
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type testTester struct {
+	*testing.T
+	failed string
+}
+
+func (t *testTester) Fatalf(message string, args ...interface{}) {
+	t.failed = fmt.Sprintf(message, args...)
+}
+
+func (t *testTester) Fatal(args ...interface{}) {
+	t.failed = fmt.Sprint(args...)
+}
+
+func TestEqual(t *testing.T) {
+	dir := t.TempDir()
+	golden := filepath.Join(dir, "test.golden")
+	assert.NoError(t, os.WriteFile(golden, []byte("hello\nworld\n"), 0600))
+
+	tester := &testTester{T: t}
+	Equal(tester, golden, "hello\nworld\n")
+	assert.Equal(t, "", tester.failed)
+
+	Equal(tester, golden, "hello\nthere\n")
+	assert.Equal(t, "Golden file "+golden+" does not match, run the tests with -goldenassert.update to update it:\n hello\n-world\n+there\n \n", tester.failed)
+
+	missing := filepath.Join(dir, "missing.golden")
+	Equal(tester, missing, "hello\n")
+	assert.Equal(t, "Golden file "+missing+" does not exist, run the tests with -goldenassert.update to create it", tester.failed)
+
+	Equal(tester, dir, "hello\n")
+	assert.Equal(t, "Could not read golden file "+dir+":\nread "+dir+": is a directory", tester.failed)
+
+	Equal(tester, missing, "hello\n", "render %s", "home", assert.Dump("user", "alice"))
+	assert.Equal(t, "Golden file "+missing+" does not exist, run the tests with -goldenassert.update to create it\nrender home\nuser: \"alice\"\n", tester.failed)
+
+	Equal(tester, golden, "hello\nthere\n", "render %s", "home")
+	assert.Equal(t, "Golden file "+golden+" does not match, run the tests with -goldenassert.update to update it:\nrender home\n hello\n-world\n+there\n \n", tester.failed)
+}
+
+func TestEqualUpdate(t *testing.T) {
+	defer func(value bool) { *update = value }(*update)
+	*update = true
+	golden := filepath.Join(t.TempDir(), "testdata", "test.golden")
+
+	tester := &testTester{T: t}
+	Equal(tester, golden, "hello\nworld\n")
+	assert.Equal(t, "", tester.failed)
+	data, err := os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, "hello\nworld\n", string(data))
+
+	Equal(tester, golden, "goodbye\n")
+	assert.Equal(t, "", tester.failed)
+	data, err = os.ReadFile(golden)
+	assert.NoError(t, err)
+	assert.Equal(t, "goodbye\n", string(data))
+
+	invalid := filepath.Join(golden, "test.golden")
+	Equal(tester, invalid, "hello\n", "render %s", "home")
+	assert.HasPrefix(t, tester.failed, "Could not update golden file "+invalid+":\n")
+	assert.HasSuffix(t, tester.failed, "\nrender home")
+}
+
+func TestFlags(t *testing.T) {
+	assert.NotZero(t, flag.Lookup("goldenassert.update"))
+	assert.NotZero(t, flag.Lookup("goldenassert.update-snapshots"))
+	assert.Zero(t, flag.Lookup("update"), "-update must be left free for the tests themselves")
+}
+
+type point struct {
+	X, Y int
+}
+
+func TestSnapshot(t *testing.T) {
+	Snapshot(t, []point{{1, 2}, {3, 4}})
+	Snapshot(t, map[string]int{"a": 1})
+
+	t.Run("Sub test", func(t *testing.T) {
+		tester := &testTester{T: t}
+		Snapshot(tester, point{1, 2})
+		assert.Equal(t, "", tester.failed)
+
+		Snapshot(tester, point{1, 3})
+		path := filepath.Join("testdata", "snapshots", "TestSnapshot_Sub_test.2.snap")
+		assert.Equal(t, "Snapshot "+path+" does not match, run the tests with -goldenassert.update-snapshots to update it:\n goldenassert.point{\n   X: 1,\n-  Y: 2,\n+  Y: 3,\n }\n \n", tester.failed)
+
+		Snapshot(tester, point{1, 2})
+		path = filepath.Join("testdata", "snapshots", "TestSnapshot_Sub_test.3.snap")
+		assert.Equal(t, "Snapshot "+path+" does not exist, run the tests with -goldenassert.update-snapshots to create it", tester.failed)
+	})
+}
+
+func TestSnapshotUpdate(t *testing.T) {
+	defer func(value bool) { *updateSnapshots = value }(*updateSnapshots)
+	*updateSnapshots = true
+	wd, err := os.Getwd()
+	assert.NoError(t, err)
+	assert.NoError(t, os.Chdir(t.TempDir()))
+	defer func() { assert.NoError(t, os.Chdir(wd)) }()
+
+	Snapshot(t, point{1, 2})
+	data, err := os.ReadFile(filepath.Join("testdata", "snapshots", "TestSnapshotUpdate.snap"))
+	assert.NoError(t, err)
+	assert.Equal(t, "goldenassert.point{\n  X: 1,\n  Y: 2,\n}\n", string(data))
+}
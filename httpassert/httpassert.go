@@ -0,0 +1,78 @@
+// Package httpassert provides assertions for HTTP responses.
+//
+// It is a separate package so that the core assert package does not depend on net/http.
+//
+// Assertions that read the response body close it and replace it with an in-memory copy, so
+// the body may be read again afterwards.
+package httpassert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+// Status asserts that the status code of "resp" is "want".
+//
+// On failure the response body is included in the message.
+func Status(t testing.TB, resp *http.Response, want int, msgAndArgs ...any) {
+	t.Helper()
+	if resp.StatusCode == want {
+		return
+	}
+	if len(msgAndArgs) == 0 {
+		msgAndArgs = []any{"Unexpected HTTP status:"}
+	}
+	body, ok := readBody(t, resp)
+	if !ok {
+		return
+	}
+	msgAndArgs = append(msgAndArgs[:len(msgAndArgs):len(msgAndArgs)], assert.Dump("Body", body))
+	assert.Equal(t, statusText(want), statusText(resp.StatusCode), msgAndArgs...)
+}
+
+// Header asserts that the first value of the header "key" in "resp" is "want".
+//
+// A missing header has the value "".
+func Header(t testing.TB, resp *http.Response, key, want string, msgAndArgs ...any) {
+	t.Helper()
+	if len(msgAndArgs) == 0 {
+		msgAndArgs = []any{"Unexpected value for HTTP header %s:", http.CanonicalHeaderKey(key)}
+	}
+	assert.Equal(t, want, resp.Header.Get(key), msgAndArgs...)
+}
+
+// BodyContains asserts that the body of "resp" contains "substr".
+func BodyContains(t testing.TB, resp *http.Response, substr string, msgAndArgs ...any) {
+	t.Helper()
+	body, ok := readBody(t, resp)
+	if !ok {
+		return
+	}
+	assert.Contains(t, body, substr, msgAndArgs...)
+}
+
+func statusText(code int) string {
+	return fmt.Sprintf("%d %s", code, http.StatusText(code))
+}
+
+// readBody reads and closes the body of "resp", replacing it with a copy that can be read
+// again. It returns false if the body could not be read.
+func readBody(t testing.TB, resp *http.Response) (string, bool) {
+	t.Helper()
+	if resp.Body == nil {
+		return "", true
+	}
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		assert.NoError(t, err, "Could not read response body:")
+		return "", false
+	}
+	return string(data), true
+}
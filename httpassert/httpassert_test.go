@@ -0,0 +1,77 @@
+package httpassert
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type testTester struct {
+	*testing.T
+	failed string
+}
+
+func (t *testTester) Fatalf(message string, args ...interface{}) {
+	t.failed = fmt.Sprintf(message, args...)
+}
+
+func (t *testTester) Fatal(args ...interface{}) {
+	t.failed = fmt.Sprint(args...)
+}
+
+func response(status int, body string) *http.Response {
+	w := httptest.NewRecorder()
+	w.Header().Set("Content-Type", "text/plain")
+	w.WriteHeader(status)
+	_, _ = io.WriteString(w, body)
+	return w.Result()
+}
+
+func TestStatus(t *testing.T) {
+	tester := &testTester{T: t}
+	Status(tester, response(http.StatusOK, "hello"), http.StatusOK)
+	assert.Equal(t, "", tester.failed)
+
+	resp := response(http.StatusNotFound, "no such user")
+	Status(tester, resp, http.StatusOK)
+	assert.Equal(t, "Unexpected HTTP status:\nExpected: \"200 OK\"\nActual:   \"404 Not Found\"\n           ^\nBody: \"no such user\"\n", tester.failed)
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "no such user", string(body))
+}
+
+func TestHeader(t *testing.T) {
+	tester := &testTester{T: t}
+	resp := response(http.StatusOK, "")
+	Header(tester, resp, "content-type", "text/plain")
+	assert.Equal(t, "", tester.failed)
+
+	Header(tester, resp, "content-type", "application/json")
+	assert.HasPrefix(t, tester.failed, "Unexpected value for HTTP header Content-Type:\n")
+
+	Header(tester, resp, "Location", "/")
+	assert.HasPrefix(t, tester.failed, "Unexpected value for HTTP header Location:\n")
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestBodyContains(t *testing.T) {
+	tester := &testTester{T: t}
+	resp := response(http.StatusOK, "hello world")
+	BodyContains(tester, resp, "world")
+	assert.Equal(t, "", tester.failed)
+
+	BodyContains(tester, resp, "goodbye")
+	assert.Equal(t, "Haystack does not contain needle.\nNeedle: \"goodbye\"\nHaystack: \"hello world\"\n", tester.failed)
+
+	resp = &http.Response{Body: io.NopCloser(failingReader{})}
+	BodyContains(tester, resp, "hello")
+	assert.Equal(t, "Could not read response body:\nconnection reset", tester.failed)
+}
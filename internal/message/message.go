@@ -0,0 +1,47 @@
+// Package message composes failure messages for the assertion subpackages.
+package message
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+// Compose returns a message and arguments for the assert package that report "message"
+// followed by the caller's message in "msgAndArgs", if any.
+//
+// Unlike passing "msgAndArgs" to an assertion directly, the caller's message never replaces
+// "message". Any values passed to assert.Dump are preserved.
+func Compose(message string, msgAndArgs []any) []any {
+	var args, dumps []any
+	for _, arg := range msgAndArgs {
+		if _, ok := arg.(assert.DumpValue); ok {
+			dumps = append(dumps, arg)
+		} else {
+			args = append(args, arg)
+		}
+	}
+	if caller := format(args); caller != "" {
+		message += "\n" + caller
+	}
+	return append([]any{"%s", message}, dumps...)
+}
+
+// format the caller's message the same way the assert package does.
+func format(args []any) string {
+	if len(args) == 0 {
+		return ""
+	}
+	if lazy, ok := args[0].(func() string); ok && len(args) == 1 {
+		return lazy()
+	}
+	if format, ok := args[0].(string); ok {
+		return fmt.Sprintf(format, args[1:]...)
+	}
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = fmt.Sprint(arg)
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,29 @@
+package message
+
+import (
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+func TestCompose(t *testing.T) {
+	dump := assert.Dump("id", 1)
+	tests := []struct {
+		name       string
+		msgAndArgs []any
+		expected   []any
+	}{
+		{"NoMessage", nil, []any{"%s", "Failed:\ndetail"}},
+		{"Format", []any{"config %s", "prod"}, []any{"%s", "Failed:\ndetail\nconfig prod"}},
+		{"Percent", []any{"100%% done"}, []any{"%s", "Failed:\ndetail\n100% done"}},
+		{"Lazy", []any{func() string { return "lazy" }}, []any{"%s", "Failed:\ndetail\nlazy"}},
+		{"Values", []any{1, true}, []any{"%s", "Failed:\ndetail\n1 true"}},
+		{"Dump", []any{"config %s", dump, "prod"}, []any{"%s", "Failed:\ndetail\nconfig prod", dump}},
+		{"OnlyDump", []any{dump}, []any{"%s", "Failed:\ndetail", dump}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			assert.Equal(t, test.expected, Compose("Failed:\ndetail", test.msgAndArgs))
+		})
+	}
+}
@@ -0,0 +1,319 @@
+package assert
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// JSONEqual asserts that "expected" and "actual" are semantically equal JSON documents.
+//
+// Key order and insignificant whitespace are ignored. Numbers are compared exactly by value,
+// so 1 and 1.0 are equal but large integers such as 64-bit IDs are never rounded. If the
+// documents differ, a diff of their normalised, indented forms will be displayed.
+func JSONEqual(t testing.TB, expected, actual string, msgAndArgs ...any) {
+	expectedValue, expectedErr := unmarshalJSON([]byte(expected))
+	actualValue, actualErr := unmarshalJSON([]byte(actual))
+	if expectedErr == nil && actualErr == nil && reflect.DeepEqual(expectedValue, actualValue) {
+		return
+	}
+	t.Helper()
+	if expectedErr != nil {
+		msg := formatMsgAndArgs("Expected value is not valid JSON:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s\n%s", msg, expectedErr, expected)
+		return
+	}
+	if actualErr != nil {
+		msg := formatMsgAndArgs("Actual value is not valid JSON:", msgAndArgs...)
+		fatalf(t, msgAndArgs, "%s\n%s\n%s", msg, actualErr, actual)
+		return
+	}
+	msg := formatMsgAndArgs("Expected JSON to be equal:", msgAndArgs...)
+	diff := Diff(normaliseJSON(expectedValue), normaliseJSON(actualValue))
+	fatalCompare(t, msgAndArgs, expected, actual, diff, msg+"\n"+colorDiff(diff))
+}
+
+// JSONRoundTrips asserts that "value" is equal to the result of marshalling it to JSON and
+// unmarshalling the JSON into a new value of the same type.
+//
+// This catches missing struct tags and lossy custom marshalers. CompareOptions such as
+// ExcludeFields may be used to ignore fields that legitimately do not round trip.
+func JSONRoundTrips[T any](t testing.TB, value T, msgArgsAndCompareOptions ...any) {
+	msgArgsAndCompareOptions, compareOptions := extractCompareOptions(msgArgsAndCompareOptions...)
+	data, marshalErr := json.Marshal(value)
+	var decoded T
+	var unmarshalErr error
+	if marshalErr == nil {
+		unmarshalErr = json.Unmarshal(data, &decoded)
+		if unmarshalErr == nil && objectsAreEqual(value, decoded, compareOptions...) {
+			return
+		}
+	}
+	t.Helper()
+	if marshalErr != nil {
+		msg := formatMsgAndArgs("Could not marshal value to JSON:", msgArgsAndCompareOptions...)
+		fatalf(t, msgArgsAndCompareOptions, "%s\n%s", msg, marshalErr)
+		return
+	}
+	if unmarshalErr != nil {
+		msg := formatMsgAndArgs("Could not unmarshal JSON:", msgArgsAndCompareOptions...)
+		fatalf(t, msgArgsAndCompareOptions, "%s\n%s\n%s", msg, unmarshalErr, data)
+		return
+	}
+	msg := formatMsgAndArgs("Value does not round trip through JSON:", msgArgsAndCompareOptions...)
+	diff := Diff(value, decoded, compareOptions...)
+	fatalCompare(t, msgArgsAndCompareOptions, value, decoded, diff, fmt.Sprintf("%s\nJSON: %s\n%s", msg, data, colorDiff(diff)))
+}
+
+// unmarshalJSON decodes a JSON document, with numbers decoded as json.Number values in a
+// canonical form so that they can be compared exactly. Decoding them as float64s would make
+// eg. distinct 64-bit IDs compare equal.
+func unmarshalJSON(data []byte) (any, error) {
+	// Validate with json.Unmarshal first, for its error messages and rejection of trailing data.
+	if err := json.Unmarshal(data, &json.RawMessage{}); err != nil {
+		return nil, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	return canonicaliseJSON(value), nil
+}
+
+// canonicaliseJSON replaces the json.Number values in a decoded JSON value with canonical
+// forms, so that eg. 1, 1.0 and 1e0 are equal.
+func canonicaliseJSON(value any) any {
+	switch value := value.(type) {
+	case map[string]any:
+		for key, elem := range value {
+			value[key] = canonicaliseJSON(elem)
+		}
+	case []any:
+		for i, elem := range value {
+			value[i] = canonicaliseJSON(elem)
+		}
+	case json.Number:
+		return canonicalJSONNumber(value)
+	}
+	return value
+}
+
+// canonicalJSONNumber returns the shortest exact decimal representation of "n".
+func canonicalJSONNumber(n json.Number) json.Number {
+	r, ok := new(big.Rat).SetString(string(n))
+	if !ok {
+		return n
+	}
+	if r.IsInt() {
+		return json.Number(r.Num().String())
+	}
+	// Decimal numbers always have a finite expansion, so this terminates.
+	for prec := 1; ; prec++ {
+		s := r.FloatString(prec)
+		if exact, _ := new(big.Rat).SetString(s); exact.Cmp(r) == 0 {
+			return json.Number(s)
+		}
+	}
+}
+
+// normaliseJSON returns the indented JSON encoding of a decoded JSON value, with sorted keys.
+func normaliseJSON(value any) string {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		panic(err) // Decoded JSON values can always be encoded.
+	}
+	return string(data)
+}
+
+// A JSONKind matches any JSON value of a particular kind, when used in a shape passed to
+// JSONMatches.
+type JSONKind string
+
+// Kinds of JSON values that may be used in a shape passed to JSONMatches.
+const (
+	JSONAny    JSONKind = "any value"
+	JSONString JSONKind = "string"
+	JSONNumber JSONKind = "number"
+	JSONBool   JSONKind = "bool"
+	JSONObject JSONKind = "object"
+	JSONArray  JSONKind = "array"
+)
+
+// A JSONOption modifies how JSONMatches matches a document against a shape.
+type JSONOption func(m *jsonMatcher)
+
+// StrictJSON causes JSONMatches to fail if an object has keys that are not in the shape.
+func StrictJSON() JSONOption {
+	return func(m *jsonMatcher) {
+		m.strict = true
+	}
+}
+
+// JSONMatches asserts that the JSON document "actual" conforms to "shape".
+//
+// A shape is a Go value mirroring the structure of the expected document. Maps with string
+// keys match objects that have at least those keys, or exactly those keys if the StrictJSON
+// option is given. Slices match arrays of the same length element by element. A JSONKind
+// matches any value of that kind, and any other value must be equal to the JSON value, eg.
+//
+//	assert.JSONMatches(t, response, map[string]any{
+//		"id":      assert.JSONNumber,
+//		"name":    "Alice",
+//		"created": assert.JSONString,
+//		"tags":    assert.JSONArray,
+//	})
+func JSONMatches(t testing.TB, actual string, shape any, msgArgsAndJSONOptions ...any) {
+	msgArgsAndJSONOptions, jsonOptions := extractOptions[JSONOption](msgArgsAndJSONOptions...)
+	actualValue, err := unmarshalJSON([]byte(actual))
+	var mismatches []string
+	if err == nil {
+		m := &jsonMatcher{}
+		for _, option := range jsonOptions {
+			option(m)
+		}
+		m.match("", reflect.ValueOf(shape), actualValue)
+		mismatches = m.mismatches
+		if len(mismatches) == 0 {
+			return
+		}
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Actual value is not valid JSON:", msgArgsAndJSONOptions...)
+		fatalf(t, msgArgsAndJSONOptions, "%s\n%s\n%s", msg, err, actual)
+		return
+	}
+	msg := formatMsgAndArgs("JSON does not match shape:", msgArgsAndJSONOptions...)
+	fatalf(t, msgArgsAndJSONOptions, "%s\n%s\n", msg, strings.Join(mismatches, "\n"))
+}
+
+type jsonMatcher struct {
+	strict     bool
+	mismatches []string
+}
+
+func (m *jsonMatcher) mismatch(path, format string, args ...any) {
+	if path == "" {
+		path = "."
+	}
+	m.mismatches = append(m.mismatches, path+": "+fmt.Sprintf(format, args...))
+}
+
+func (m *jsonMatcher) match(path string, shape reflect.Value, actual any) {
+	for shape.IsValid() && shape.Kind() == reflect.Interface {
+		shape = shape.Elem()
+	}
+	if kind, ok := jsonKindOf(shape); ok {
+		if kind != JSONAny && kind != jsonKind(actual) {
+			m.mismatch(path, "expected %s but got %s", kind, describeJSON(actual))
+		}
+		return
+	}
+	switch {
+	case !shape.IsValid() || (shape.Kind() == reflect.Map || shape.Kind() == reflect.Slice || shape.Kind() == reflect.Ptr) && shape.IsNil():
+		if actual != nil {
+			m.mismatch(path, "expected null but got %s", describeJSON(actual))
+		}
+
+	case shape.Kind() == reflect.Map && shape.Type().Key().Kind() == reflect.String:
+		object, ok := actual.(map[string]any)
+		if !ok {
+			m.mismatch(path, "expected object but got %s", describeJSON(actual))
+			return
+		}
+		keys := shape.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		for _, key := range keys {
+			value, ok := object[key.String()]
+			if !ok {
+				m.mismatch(path+"."+key.String(), "missing key")
+				continue
+			}
+			m.match(path+"."+key.String(), shape.MapIndex(key), value)
+		}
+		if m.strict {
+			extra := []string{}
+			for key := range object {
+				if !shape.MapIndex(reflect.ValueOf(key).Convert(shape.Type().Key())).IsValid() {
+					extra = append(extra, key)
+				}
+			}
+			sort.Strings(extra)
+			for _, key := range extra {
+				m.mismatch(path+"."+key, "unexpected key")
+			}
+		}
+
+	case shape.Kind() == reflect.Slice || shape.Kind() == reflect.Array:
+		array, ok := actual.([]any)
+		if !ok {
+			m.mismatch(path, "expected array but got %s", describeJSON(actual))
+			return
+		}
+		if len(array) != shape.Len() {
+			m.mismatch(path, "expected %d elements but got %d", shape.Len(), len(array))
+			return
+		}
+		for i := range array {
+			m.match(fmt.Sprintf("%s[%d]", path, i), shape.Index(i), array[i])
+		}
+
+	default:
+		// Round trip the shape through JSON so that eg. ints compare equal to JSON numbers.
+		data, err := json.Marshal(shape.Interface())
+		var expected any
+		if err == nil {
+			expected, err = unmarshalJSON(data)
+		}
+		if err != nil {
+			m.mismatch(path, "invalid shape %T: %s", shape.Interface(), err)
+			return
+		}
+		if !reflect.DeepEqual(expected, actual) {
+			m.mismatch(path, "expected %s but got %s", data, describeJSON(actual))
+		}
+	}
+}
+
+func jsonKindOf(shape reflect.Value) (JSONKind, bool) {
+	if !shape.IsValid() || shape.Type() != reflect.TypeOf(JSONAny) {
+		return "", false
+	}
+	return shape.Interface().(JSONKind), true
+}
+
+// jsonKind returns the kind of a decoded JSON value.
+func jsonKind(value any) JSONKind {
+	switch value.(type) {
+	case string:
+		return JSONString
+	case json.Number:
+		return JSONNumber
+	case bool:
+		return JSONBool
+	case map[string]any:
+		return JSONObject
+	case []any:
+		return JSONArray
+	default:
+		return "null"
+	}
+}
+
+// describeJSON returns a short description of a decoded JSON value for failure messages.
+func describeJSON(value any) string {
+	switch value.(type) {
+	case map[string]any, []any:
+		return string(jsonKind(value))
+	default:
+		data, _ := json.Marshal(value)
+		return fmt.Sprintf("%s %s", jsonKind(value), data)
+	}
+}
@@ -0,0 +1,153 @@
+package assert
+
+import (
+	"testing"
+	"time"
+)
+
+func TestJSONEqual(t *testing.T) {
+	assertOk(t, "Identical", func(t testing.TB) {
+		JSONEqual(t, `{"a": 1, "b": [1, 2]}`, `{"a": 1, "b": [1, 2]}`)
+	})
+	assertOk(t, "KeyOrderAndWhitespace", func(t testing.TB) {
+		JSONEqual(t, `{"a": 1, "b": {"c": true, "d": null}}`, "{\"b\":{\"d\":null,\"c\":true},\n\"a\":1.0}")
+	})
+	assertFail(t, "DifferentValue", func(t testing.TB) {
+		JSONEqual(t, `{"a": 1, "b": [1, 2]}`, `{"a": 1, "b": [2, 1]}`)
+	})
+	assertFail(t, "LargeIntegers", func(t testing.TB) {
+		JSONEqual(t, `{"id": 9007199254740993}`, `{"id": 9007199254740992}`)
+	})
+	assertOk(t, "EquivalentNumbers", func(t testing.TB) {
+		JSONEqual(t, `[1, 1.5, 100, 0.25, -3]`, `[1.0, 1.50, 1e2, 25e-2, -3.000]`)
+	})
+	assertFail(t, "PreciseDecimals", func(t testing.TB) {
+		JSONEqual(t, `[0.1]`, `[0.10000000000000000001]`)
+	})
+	assertFail(t, "InvalidExpected", func(t testing.TB) {
+		JSONEqual(t, `{"a": `, `{"a": 1}`)
+	})
+	assertFail(t, "InvalidActual", func(t testing.TB) {
+		JSONEqual(t, `{"a": 1}`, `{"a": 1`)
+	})
+}
+
+func TestJSONEqualMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	JSONEqual(tester, `{"a": 1}`, `{"a": 1`)
+	Equal(t, "Actual value is not valid JSON:\nunexpected end of JSON input\n{\"a\": 1", tester.failed)
+	tester = &testTester{T: t}
+	JSONEqual(tester, `{"b": 2, "a": 1}`, `{"a": 1, "b": 3}`)
+	Equal(t, "Expected JSON to be equal:\n {\n   \"a\": 1,\n-  \"b\": 2\n+  \"b\": 3\n }\n", tester.failed)
+	tester = &testTester{T: t}
+	JSONEqual(tester, `{"id": 9007199254740993, "n": 1.0}`, `{"id": 9007199254740992, "n": 1}`)
+	Equal(t, "Expected JSON to be equal:\n {\n-  \"id\": 9007199254740993,\n+  \"id\": 9007199254740992,\n   \"n\": 1\n }\n", tester.failed)
+}
+
+type jsonUser struct {
+	Name     string `json:"name"`
+	Password string `json:"-"`
+	Admin    bool   `json:"admin,omitempty"`
+}
+
+type lossyTime struct {
+	At time.Time
+}
+
+func TestJSONRoundTrips(t *testing.T) {
+	assertOk(t, "Struct", func(t testing.TB) {
+		JSONRoundTrips(t, jsonUser{Name: "Alice", Admin: true})
+	})
+	assertOk(t, "Pointer", func(t testing.TB) {
+		JSONRoundTrips(t, &jsonUser{Name: "Alice"})
+	})
+	assertOk(t, "Map", func(t testing.TB) {
+		JSONRoundTrips(t, map[string][]int{"a": {1, 2}})
+	})
+	assertFail(t, "IgnoredField", func(t testing.TB) {
+		JSONRoundTrips(t, jsonUser{Name: "Alice", Password: "secret"})
+	})
+	assertOk(t, "ExcludeFields", func(t testing.TB) {
+		JSONRoundTrips(t, jsonUser{Name: "Alice", Password: "secret"}, ExcludeFields("Password"))
+	})
+	assertFail(t, "Unmarshallable", func(t testing.TB) {
+		JSONRoundTrips(t, make(chan int))
+	})
+	assertFail(t, "Lossy", func(t testing.TB) {
+		JSONRoundTrips(t, lossyTime{At: time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("X", 3600))})
+	})
+}
+
+func TestJSONRoundTripsMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	JSONRoundTrips(tester, jsonUser{Name: "Alice", Password: "secret"})
+	Equal(t, `Value does not round trip through JSON:
+JSON: {"name":"Alice"}
+ assert.jsonUser{
+   Name: "Alice",
+-  Password: "secret",
+ }
+`, tester.failed)
+	JSONRoundTrips(tester, make(chan int))
+	Equal(t, "Could not marshal value to JSON:\njson: unsupported type: chan int", tester.failed)
+}
+
+func TestJSONMatches(t *testing.T) {
+	document := `{"id": 42, "name": "Alice", "created": "2024-01-02T03:04:05Z", "tags": ["a", "b"], "admin": false, "manager": null}`
+	assertOk(t, "Kinds", func(t testing.TB) {
+		JSONMatches(t, document, map[string]any{
+			"id":      JSONNumber,
+			"name":    JSONString,
+			"created": JSONString,
+			"tags":    JSONArray,
+			"admin":   JSONBool,
+			"manager": JSONAny,
+		})
+	})
+	assertOk(t, "Values", func(t testing.TB) {
+		JSONMatches(t, document, map[string]any{"id": 42, "name": "Alice", "tags": []any{"a", JSONString}, "manager": nil})
+	})
+	assertOk(t, "AdditionalKeys", func(t testing.TB) {
+		JSONMatches(t, document, map[string]any{"id": JSONNumber})
+	})
+	assertFail(t, "Strict", func(t testing.TB) {
+		JSONMatches(t, document, map[string]any{"id": JSONNumber}, StrictJSON())
+	})
+	assertFail(t, "MissingKey", func(t testing.TB) {
+		JSONMatches(t, document, map[string]any{"email": JSONString})
+	})
+	assertFail(t, "WrongKind", func(t testing.TB) {
+		JSONMatches(t, document, map[string]any{"id": JSONString})
+	})
+	assertFail(t, "InvalidJSON", func(t testing.TB) {
+		JSONMatches(t, `{"id": `, map[string]any{"id": JSONNumber})
+	})
+	assertFail(t, "LargeIntegers", func(t testing.TB) {
+		JSONMatches(t, `{"id": 9007199254740992}`, map[string]any{"id": int64(9007199254740993)})
+	})
+	assertOk(t, "LargeIntegersEqual", func(t testing.TB) {
+		JSONMatches(t, `{"id": 9007199254740993}`, map[string]any{"id": int64(9007199254740993)})
+	})
+	assertOk(t, "EquivalentNumbers", func(t testing.TB) {
+		JSONMatches(t, `{"n": 1.0, "f": 1.50}`, map[string]any{"n": 1, "f": 1.5})
+	})
+}
+
+func TestJSONMatchesMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	JSONMatches(tester, `{"id": "42", "user": {"name": "Alice", "roles": ["admin"]}, "extra": 1}`, map[string]any{
+		"id": JSONNumber,
+		"user": map[string]any{
+			"name":  "Bob",
+			"email": JSONString,
+			"roles": []any{JSONString, JSONString},
+		},
+	}, StrictJSON())
+	Equal(t, `JSON does not match shape:
+.id: expected number but got string "42"
+.user.email: missing key
+.user.name: expected "Bob" but got string "Alice"
+.user.roles: expected 2 elements but got 1
+.extra: unexpected key
+`, tester.failed)
+}
@@ -0,0 +1,137 @@
+package assert
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// A normaliser transforms a copy of a value before comparison.
+//
+// Normalisers are applied to every value in a tree, children first, and may
+// modify the value they are passed in place. Map keys are not normalised, as
+// distinct keys could otherwise collide.
+type normaliser func(v reflect.Value) reflect.Value
+
+// normalise returns a copy of value with all normalisers applied.
+//
+// The original value is never modified.
+func (o *compareOptions) normalise(value any) any {
+	if (len(o.normalisers) == 0 && !o.ignoreUnexported) || value == nil {
+		return value
+	}
+	n := &normaliseState{normalisers: o.normalisers, ignoreUnexported: o.ignoreUnexported, seen: map[normalisedPointer]reflect.Value{}}
+	return n.value(reflect.ValueOf(value)).Interface()
+}
+
+type normaliseState struct {
+	normalisers []normaliser
+	// ignoreUnexported zeroes unexported struct fields, other than those of time.Time.
+	ignoreUnexported bool
+	seen             map[normalisedPointer]reflect.Value
+}
+
+// normalisedPointer identifies a pointer that has already been copied.
+//
+// The type is required as a pointer to a struct and a pointer to its first field have the
+// same address.
+type normalisedPointer struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+func (n *normaliseState) value(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		key := normalisedPointer{v.Pointer(), v.Type()}
+		if out, ok := n.seen[key]; ok {
+			return out
+		}
+		out := reflect.New(v.Type().Elem())
+		n.seen[key] = out
+		out.Elem().Set(n.value(readable(v.Elem())))
+		v = out
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(n.value(readable(v.Elem())))
+		v = out
+
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < out.NumField(); i++ {
+			field := settable(out.Field(i))
+			if n.ignoreUnexported && !v.Type().Field(i).IsExported() && v.Type() != timeType {
+				field.Set(reflect.Zero(field.Type()))
+				continue
+			}
+			field.Set(n.value(field))
+		}
+		v = out
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(n.value(readable(v.Index(i))))
+		}
+		v = out
+
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(n.value(readable(v.Index(i))))
+		}
+		v = out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(readable(iter.Key()), n.value(readable(iter.Value())))
+		}
+		v = out
+
+	default:
+	}
+	for _, normaliser := range n.normalisers {
+		v = normaliser(v)
+	}
+	return v
+}
+
+// settable returns a settable version of v, which must be addressable.
+//
+// This allows unexported struct fields to be modified.
+func settable(v reflect.Value) reflect.Value {
+	if v.CanSet() {
+		return v
+	}
+	return reflect.NewAt(v.Type(), unsafe.Pointer(v.UnsafeAddr())).Elem()
+}
+
+// readable returns a version of v that can be used with reflect.Value.Set.
+//
+// Values read through unexported struct fields can not otherwise be used.
+func readable(v reflect.Value) reflect.Value {
+	if v.CanInterface() {
+		return v
+	}
+	if v.CanAddr() {
+		return settable(v)
+	}
+	out := reflect.New(v.Type()).Elem()
+	settable(out).Set(v) // Will panic if v is not exported.
+	return out
+}
@@ -0,0 +1,67 @@
+package assert
+
+import (
+	"reflect"
+	"testing"
+)
+
+type private struct {
+	name     string
+	children []*private
+}
+
+func TestNormaliseDoesNotModifyOriginal(t *testing.T) {
+	model := &Model{ID: 1, Name: "parent", Children: []*Model{{ID: 2, Name: "child"}}}
+	opts := expandCompareOptions(ExcludeFields("Name"))
+	normalised := opts.normalise(model).(*Model)
+	Equal(t, &Model{ID: 1, Children: []*Model{{ID: 2}}}, normalised)
+	Equal(t, "parent", model.Name)
+	Equal(t, "child", model.Children[0].Name)
+}
+
+func TestNormaliseUnexported(t *testing.T) {
+	value := private{name: "parent", children: []*private{{name: "child"}}}
+	opts := expandCompareOptions(func(o *compareOptions) {
+		o.normalisers = append(o.normalisers, func(v reflect.Value) reflect.Value {
+			if v.Kind() == reflect.String {
+				return reflect.ValueOf("name")
+			}
+			return v
+		})
+	})
+	normalised := opts.normalise(value).(private)
+	Equal(t, "name", normalised.name)
+	Equal(t, "name", normalised.children[0].name)
+	Equal(t, "child", value.children[0].name)
+}
+
+func TestNormaliseCycle(t *testing.T) {
+	type node struct {
+		Name string
+		Next *node
+	}
+	value := &node{Name: "a"}
+	value.Next = value
+	opts := expandCompareOptions(ExcludeFields("Name"))
+	normalised := opts.normalise(value).(*node)
+	True(t, normalised == normalised.Next)
+	Equal(t, "", normalised.Name)
+}
+
+func TestNormaliseAliasedFirstField(t *testing.T) {
+	type inner struct{ Name string }
+	type outer struct {
+		In   inner
+		Name string
+	}
+	type holder struct {
+		Outer *outer
+		In    *inner
+	}
+	value := &outer{In: inner{Name: "in"}, Name: "out"}
+	opts := expandCompareOptions(IgnoreCase())
+	normalised := opts.normalise(holder{value, &value.In}).(holder)
+	Equal(t, "in", normalised.In.Name)
+	Equal(t, "out", normalised.Outer.Name)
+	Equal(t, holder{value, &value.In}, holder{&outer{In: inner{Name: "IN"}, Name: "OUT"}, &inner{Name: "In"}}, IgnoreCase())
+}
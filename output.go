@@ -0,0 +1,54 @@
+package assert
+
+import (
+	"io"
+	"os"
+	"testing"
+)
+
+// Output calls "fn" and returns everything it wrote to os.Stdout and os.Stderr.
+//
+// os.Stdout and os.Stderr are replaced with pipes while "fn" runs, and restored afterwards
+// even if it panics. As they are global, Output must not be used in parallel tests. Writers
+// that captured the original files before the call, such as the default logger of the log
+// package, are not affected.
+func Output(t testing.TB, fn func()) (stdout, stderr string) {
+	t.Helper()
+	// Return after a failure, as "t" may be eg. a Collector that does not stop the test.
+	stdoutR, stdoutW, err := os.Pipe()
+	if err != nil {
+		NoError(t, err)
+		return "", ""
+	}
+	stderrR, stderrW, err := os.Pipe()
+	if err != nil {
+		stdoutR.Close()
+		stdoutW.Close()
+		NoError(t, err)
+		return "", ""
+	}
+	// Drain the pipes concurrently so that "fn" can not block on a full pipe.
+	stdoutCh, stderrCh := drain(stdoutR), drain(stderrR)
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	func() {
+		defer func() {
+			os.Stdout, os.Stderr = originalStdout, originalStderr
+			stdoutW.Close()
+			stderrW.Close()
+		}()
+		os.Stdout, os.Stderr = stdoutW, stderrW
+		fn()
+	}()
+	return <-stdoutCh, <-stderrCh
+}
+
+// drain reads "r" until EOF in a goroutine, then sends the data to the returned channel.
+func drain(r *os.File) <-chan string {
+	ch := make(chan string, 1)
+	go func() {
+		defer r.Close()
+		data, _ := io.ReadAll(r)
+		ch <- string(data)
+	}()
+	return ch
+}
@@ -0,0 +1,33 @@
+package assert
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestOutput(t *testing.T) {
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	stdout, stderr := Output(t, func() {
+		fmt.Println("hello")
+		fmt.Fprintln(os.Stderr, "world")
+	})
+	Equal(t, "hello\n", stdout)
+	Equal(t, "world\n", stderr)
+	True(t, os.Stdout == originalStdout && os.Stderr == originalStderr)
+}
+
+func TestOutputLarge(t *testing.T) {
+	large := strings.Repeat("0123456789abcdef\n", 100000)
+	stdout, _ := Output(t, func() { fmt.Print(large) })
+	Equal(t, len(large), len(stdout))
+}
+
+func TestOutputRestoresAfterPanic(t *testing.T) {
+	originalStdout, originalStderr := os.Stdout, os.Stderr
+	Panics(t, func() {
+		Output(t, func() { panic("boom") })
+	})
+	True(t, os.Stdout == originalStdout && os.Stderr == originalStderr)
+}
@@ -0,0 +1,46 @@
+// Package protoassert provides assertions for protobuf messages.
+//
+// It is a separate package so that the core assert package does not depend on protobuf.
+package protoassert
+
+import (
+	"testing"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alecthomas/assert/v2/internal/message"
+)
+
+// Equal asserts that "expected" and "actual" are equal according to proto.Equal.
+//
+// Unlike assert.Equal, this ignores the internal state of messages and compares unknown
+// fields. If the messages differ, a diff of their text representations will be displayed.
+func Equal(t testing.TB, expected, actual proto.Message, msgAndArgs ...any) {
+	if proto.Equal(expected, actual) {
+		return
+	}
+	t.Helper()
+	expectedText, actualText := format(expected), format(actual)
+	if expectedText != actualText {
+		assert.Equal(t, expectedText, actualText, msgAndArgs...)
+		return
+	}
+	// Unknown fields may differ in their encoding without their text differing.
+	assert.FailNow(t, message.Compose("Expected messages to be equal but both were:\n"+expectedText, msgAndArgs)...)
+}
+
+// format a message as its type name followed by its text representation, including
+// unknown fields.
+func format(message proto.Message) string {
+	if message == nil {
+		return "<nil>"
+	}
+	name := string(message.ProtoReflect().Descriptor().FullName())
+	if !message.ProtoReflect().IsValid() {
+		return name + "(nil)"
+	}
+	text := prototext.MarshalOptions{Multiline: true, EmitUnknown: true}.Format(message)
+	return name + " {\n" + text + "}"
+}
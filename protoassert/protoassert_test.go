@@ -0,0 +1,70 @@
+package protoassert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type testTester struct {
+	*testing.T
+	failed string
+}
+
+func (t *testTester) Fatalf(message string, args ...interface{}) {
+	t.failed = fmt.Sprintf(message, args...)
+}
+
+func (t *testTester) Fatal(args ...interface{}) {
+	t.failed = fmt.Sprint(args...)
+}
+
+func field(name string, number int32) *descriptorpb.FieldDescriptorProto {
+	return &descriptorpb.FieldDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+}
+
+func TestEqual(t *testing.T) {
+	tester := &testTester{T: t}
+	Equal(tester, field("id", 1), field("id", 1))
+	assert.Equal(t, "", tester.failed)
+
+	Equal(tester, (*descriptorpb.FieldDescriptorProto)(nil), (*descriptorpb.FieldDescriptorProto)(nil))
+	assert.Equal(t, "", tester.failed)
+
+	Equal(tester, field("id", 1), field("id", 2))
+	assert.HasPrefix(t, tester.failed, "Expected values to be equal:\n google.protobuf.FieldDescriptorProto {\n")
+	assert.Contains(t, tester.failed, "\n-number:")
+	assert.Contains(t, tester.failed, "\n+number:")
+
+	Equal(tester, field("id", 1), wrapperspb.String("id"))
+	assert.Contains(t, tester.failed, "-google.protobuf.FieldDescriptorProto {\n")
+	assert.Contains(t, tester.failed, "+google.protobuf.StringValue {\n")
+
+	Equal(tester, field("id", 1), nil)
+	assert.Contains(t, tester.failed, "\n+<nil>\n")
+
+	Equal(tester, &descriptorpb.FieldDescriptorProto{}, (*descriptorpb.FieldDescriptorProto)(nil))
+	assert.Equal(t, "Expected values to be equal:\n-google.protobuf.FieldDescriptorProto {\n-}\n+google.protobuf.FieldDescriptorProto(nil)\n", tester.failed)
+
+	// Unknown fields with different encodings of the same value have the same text.
+	expected, actual := wrapperspb.String("id"), wrapperspb.String("id")
+	expected.ProtoReflect().SetUnknown(protoreflect.RawFields{0xa0, 0x06, 0x01})
+	actual.ProtoReflect().SetUnknown(protoreflect.RawFields{0xa0, 0x06, 0x81, 0x00})
+	Equal(tester, expected, actual)
+	assert.Equal(t, "Expected messages to be equal but both were:\ngoogle.protobuf.StringValue {\nvalue: \"id\"\n100: 1\n}", stripSpaces(tester.failed))
+	Equal(tester, expected, actual, "values %s", "differ")
+	assert.Equal(t, "Expected messages to be equal but both were:\ngoogle.protobuf.StringValue {\nvalue: \"id\"\n100: 1\n}\nvalues differ", stripSpaces(tester.failed))
+}
+
+// stripSpaces removes the random spaces that prototext inserts into its output to prevent
+// it from being relied upon.
+func stripSpaces(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, ":  ", ": "), "  ", "")
+}
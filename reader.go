@@ -0,0 +1,176 @@
+package assert
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"testing"
+)
+
+// DefaultReadLimit is the maximum number of bytes read from a reader by ReaderContains and
+// ReadersEqual, unless overridden with the ReadLimit option.
+const DefaultReadLimit = 16 << 20
+
+// A ReaderOption modifies how ReaderContains and ReadersEqual read from readers.
+type ReaderOption func(o *readerOptions)
+
+type readerOptions struct {
+	limit int64
+}
+
+// ReadLimit sets the maximum number of bytes that ReaderContains and ReadersEqual will read.
+func ReadLimit(limit int64) ReaderOption {
+	return func(o *readerOptions) {
+		o.limit = limit
+	}
+}
+
+func expandReaderOptions(options []ReaderOption) *readerOptions {
+	opts := &readerOptions{limit: DefaultReadLimit}
+	for _, option := range options {
+		option(opts)
+	}
+	return opts
+}
+
+// limitReader returns a reader that reads one byte more than "limit" from "r", so that
+// readers exceeding the limit can be detected.
+func limitReader(r io.Reader, limit int64) io.Reader {
+	if limit < math.MaxInt64 {
+		limit++
+	}
+	return io.LimitReader(r, limit)
+}
+
+// ReaderContains asserts that the data read from "r" contains "needle".
+//
+// At most DefaultReadLimit bytes are read, or the limit given by the ReadLimit option. The
+// assertion fails if the reader returns an error or has more data than the limit.
+func ReaderContains(t testing.TB, r io.Reader, needle string, msgArgsAndReaderOptions ...any) {
+	msgArgsAndReaderOptions, readerOptions := extractOptions[ReaderOption](msgArgsAndReaderOptions...)
+	limit := expandReaderOptions(readerOptions).limit
+	data, err := io.ReadAll(limitReader(r, limit))
+	if err == nil && int64(len(data)) <= limit && strings.Contains(string(data), needle) {
+		return
+	}
+	t.Helper()
+	if err != nil {
+		msg := formatMsgAndArgs("Error reading from reader:", msgArgsAndReaderOptions...)
+		fatalf(t, msgArgsAndReaderOptions, "%s\n%s", msg, err)
+		return
+	}
+	if int64(len(data)) > limit {
+		msg := formatMsgAndArgs("Reader exceeded read limit:", msgArgsAndReaderOptions...)
+		fatalf(t, msgArgsAndReaderOptions, "%s\nLimit: %d bytes\n", msg, limit)
+		return
+	}
+	msg := formatMsgAndArgs("Haystack does not contain needle.", msgArgsAndReaderOptions...)
+	fatalf(t, msgArgsAndReaderOptions, "%s\nNeedle: %q\nHaystack: %q\n", msg, needle, string(data))
+}
+
+// readersChunkSize is the number of bytes compared at a time by ReadersEqual. It must be a
+// multiple of hexDumpWidth.
+const readersChunkSize = 4096
+
+// ReadersEqual asserts that "expected" and "actual" produce identical data.
+//
+// The readers are compared a chunk at a time, and on failure the offset of the first
+// difference is reported with a hex dump of the surrounding bytes. At most DefaultReadLimit
+// bytes are read from each reader, or the limit given by the ReadLimit option.
+func ReadersEqual(t testing.TB, expected, actual io.Reader, msgArgsAndReaderOptions ...any) {
+	msgArgsAndReaderOptions, readerOptions := extractOptions[ReaderOption](msgArgsAndReaderOptions...)
+	limit := expandReaderOptions(readerOptions).limit
+	expectedStream := &chunkedReader{r: limitReader(expected, limit)}
+	actualStream := &chunkedReader{r: limitReader(actual, limit)}
+	for {
+		expectedErr := expectedStream.next()
+		actualErr := actualStream.next()
+		if expectedErr != nil || actualErr != nil {
+			t.Helper()
+			msg := formatMsgAndArgs("Error reading from reader:", msgArgsAndReaderOptions...)
+			if expectedErr != nil {
+				fatalf(t, msgArgsAndReaderOptions, "%s\nExpected: %s", msg, expectedErr)
+			} else {
+				fatalf(t, msgArgsAndReaderOptions, "%s\nActual: %s", msg, actualErr)
+			}
+			return
+		}
+		index := mismatchIndex(expectedStream.chunk, actualStream.chunk)
+		if index == -1 && len(expectedStream.chunk) == 0 {
+			return
+		}
+		exceeded := expectedStream.offset+int64(len(expectedStream.chunk)) > limit || actualStream.offset+int64(len(actualStream.chunk)) > limit
+		if exceeded && (index == -1 || expectedStream.offset+int64(index) >= limit) {
+			t.Helper()
+			msg := formatMsgAndArgs("Reader exceeded read limit:", msgArgsAndReaderOptions...)
+			fatalf(t, msgArgsAndReaderOptions, "%s\nLimit: %d bytes\n", msg, limit)
+			return
+		}
+		if index != -1 {
+			t.Helper()
+			msg := formatMsgAndArgs("Readers are not equal:", msgArgsAndReaderOptions...)
+			fatalf(t, msgArgsAndReaderOptions, "%s\nFirst difference at offset %#x\nExpected:\n%sActual:\n%s", msg,
+				expectedStream.offset+int64(index), expectedStream.context(index), actualStream.context(index))
+			return
+		}
+	}
+}
+
+// mismatchIndex returns the index of the first byte that differs between a and b, or -1 if
+// they are equal.
+func mismatchIndex(a, b []byte) int {
+	if bytes.Equal(a, b) {
+		return -1
+	}
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return i
+		}
+	}
+	if len(a) < len(b) {
+		return len(a)
+	}
+	return len(b)
+}
+
+// chunkedReader reads a stream a chunk at a time, retaining the end of the previous chunk
+// so that context can be displayed around a difference.
+type chunkedReader struct {
+	r        io.Reader
+	buf      [readersChunkSize]byte
+	previous []byte
+	chunk    []byte
+	offset   int64 // Offset of chunk in the stream.
+}
+
+// next reads the next chunk. The chunk is empty at the end of the stream.
+func (c *chunkedReader) next() error {
+	c.offset += int64(len(c.chunk))
+	if len(c.chunk) >= 2*hexDumpWidth {
+		c.previous = append(c.previous[:0], c.chunk[len(c.chunk)-2*hexDumpWidth:]...)
+	}
+	n, err := io.ReadFull(c.r, c.buf[:])
+	c.chunk = c.buf[:n]
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return nil
+	}
+	return err
+}
+
+// context returns a hex dump of the rows either side of chunk[index].
+func (c *chunkedReader) context(index int) string {
+	// Read a little more so that a difference at the end of a chunk has context after it.
+	following := make([]byte, 2*hexDumpWidth)
+	n, _ := io.ReadFull(c.r, following)
+	data := append(append(append([]byte{}, c.previous...), c.chunk...), following[:n]...)
+	index += len(c.previous)
+	from := index - index%hexDumpWidth - hexDumpWidth
+	to := index - index%hexDumpWidth + 2*hexDumpWidth
+	out := hexDumpAt(data, c.offset-int64(len(c.previous)), from, to, index, index+1)
+	if index >= len(data) {
+		out += fmt.Sprintf("End of data at offset %#x\n", c.offset+int64(len(c.chunk)+n))
+	}
+	return out
+}
@@ -0,0 +1,104 @@
+package assert
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReaderContains(t *testing.T) {
+	assertOk(t, "Found", func(t testing.TB) {
+		ReaderContains(t, strings.NewReader("a haystack with a needle in it"), "needle")
+	})
+	assertFail(t, "NotFound", func(t testing.TB) {
+		ReaderContains(t, strings.NewReader("a haystack with a needle in it"), "screw")
+	})
+	assertOk(t, "WithinLimit", func(t testing.TB) {
+		ReaderContains(t, strings.NewReader("needle"), "needle", ReadLimit(6))
+	})
+	assertFail(t, "ExceedsLimit", func(t testing.TB) {
+		ReaderContains(t, strings.NewReader("a needle"), "needle", ReadLimit(6))
+	})
+	assertOk(t, "MaxLimit", func(t testing.TB) {
+		ReaderContains(t, strings.NewReader("a needle"), "needle", ReadLimit(math.MaxInt64))
+	})
+	assertFail(t, "ReadError", func(t testing.TB) {
+		ReaderContains(t, io.MultiReader(strings.NewReader("needle"), &errorReader{errors.New("connection reset")}), "needle")
+	})
+}
+
+func TestReaderContainsMessage(t *testing.T) {
+	tester := &testTester{T: t}
+	ReaderContains(tester, io.MultiReader(strings.NewReader("needle"), &errorReader{errors.New("connection reset")}), "needle")
+	Equal(t, "Error reading from reader:\nconnection reset", tester.failed)
+	ReaderContains(tester, strings.NewReader("a needle"), "needle", ReadLimit(6))
+	Equal(t, "Reader exceeded read limit:\nLimit: 6 bytes\n", tester.failed)
+}
+
+func TestReadersEqual(t *testing.T) {
+	large := bytes.Repeat([]byte("0123456789abcdef"), 1000)
+	assertOk(t, "Equal", func(t testing.TB) {
+		ReadersEqual(t, bytes.NewReader(large), bytes.NewReader(large))
+	})
+	assertOk(t, "Empty", func(t testing.TB) {
+		ReadersEqual(t, strings.NewReader(""), strings.NewReader(""))
+	})
+	assertOk(t, "DifferentReadSizes", func(t testing.TB) {
+		ReadersEqual(t, bytes.NewReader(large), iotest.OneByteReader(bytes.NewReader(large)))
+	})
+	assertFail(t, "Different", func(t testing.TB) {
+		ReadersEqual(t, strings.NewReader("hello world"), strings.NewReader("hello there"))
+	})
+	assertFail(t, "Shorter", func(t testing.TB) {
+		ReadersEqual(t, bytes.NewReader(large), bytes.NewReader(large[:len(large)-1]))
+	})
+	assertFail(t, "ExceedsLimit", func(t testing.TB) {
+		ReadersEqual(t, bytes.NewReader(large), bytes.NewReader(large), ReadLimit(100))
+	})
+	assertOk(t, "MaxLimit", func(t testing.TB) {
+		ReadersEqual(t, bytes.NewReader(large), bytes.NewReader(large), ReadLimit(math.MaxInt64))
+	})
+	assertFail(t, "ReadError", func(t testing.TB) {
+		ReadersEqual(t, strings.NewReader("hello"), &errorReader{errors.New("connection reset")})
+	})
+}
+
+func TestReadersEqualMessage(t *testing.T) {
+	expected := bytes.Repeat([]byte("0123456789abcdef"), 512)
+	actual := append([]byte{}, expected...)
+	actual[4100] = 'X'
+	tester := &testTester{T: t}
+	ReadersEqual(tester, bytes.NewReader(expected), bytes.NewReader(actual))
+	Equal(t, `Readers are not equal:
+First difference at offset 0x1004
+Expected:
+00000ff0  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+00001000  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+                      ^^
+00001010  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+Actual:
+00000ff0  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+00001000  30 31 32 33 58 35 36 37  38 39 61 62 63 64 65 66  |0123X56789abcdef|
+                      ^^
+00001010  30 31 32 33 34 35 36 37  38 39 61 62 63 64 65 66  |0123456789abcdef|
+`, tester.failed)
+
+	ReadersEqual(tester, strings.NewReader("hello world"), strings.NewReader("hello"))
+	Equal(t, `Readers are not equal:
+First difference at offset 0x5
+Expected:
+00000000  68 65 6c 6c 6f 20 77 6f  72 6c 64                 |hello world|
+                         ^^
+Actual:
+00000000  68 65 6c 6c 6f                                    |hello|
+End of data at offset 0x5
+`, tester.failed)
+}
+
+type errorReader struct{ err error }
+
+func (e *errorReader) Read([]byte) (int, error) { return 0, e.err }
@@ -0,0 +1,46 @@
+// Package yamlassert provides assertions for YAML documents.
+//
+// It is a separate package so that the core assert package does not depend on a YAML library.
+package yamlassert
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+	"github.com/alecthomas/assert/v2/internal/message"
+	"gopkg.in/yaml.v3"
+)
+
+// Equal asserts that "expected" and "actual" are semantically equal YAML documents.
+//
+// Documents are compared in a normalised form, so key order, formatting and comments are
+// ignored. If the documents differ, a diff of their normalised forms will be displayed.
+func Equal(t testing.TB, expected, actual string, msgAndArgs ...any) {
+	t.Helper()
+	expectedYAML, err := normalise(expected)
+	if err != nil {
+		assert.FailNow(t, message.Compose(fmt.Sprintf("Expected value is not valid YAML:\n%s\n%s", err, expected), msgAndArgs)...)
+		return
+	}
+	actualYAML, err := normalise(actual)
+	if err != nil {
+		assert.FailNow(t, message.Compose(fmt.Sprintf("Actual value is not valid YAML:\n%s\n%s", err, actual), msgAndArgs)...)
+		return
+	}
+	assert.Equal(t, expectedYAML, actualYAML, msgAndArgs...)
+}
+
+// normalise decodes a YAML document and re-encodes it with sorted keys and consistent formatting.
+func normalise(document string) (string, error) {
+	var value any
+	if err := yaml.Unmarshal([]byte(document), &value); err != nil {
+		return "", err
+	}
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
@@ -0,0 +1,47 @@
+package yamlassert
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/alecthomas/assert/v2"
+)
+
+type testTester struct {
+	*testing.T
+	failed string
+}
+
+func (t *testTester) Fatalf(message string, args ...interface{}) {
+	t.failed = fmt.Sprintf(message, args...)
+}
+
+func (t *testTester) Fatal(args ...interface{}) {
+	t.failed = fmt.Sprint(args...)
+}
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		actual   string
+		failed   string
+	}{
+		{"Identical", "a: 1\nb: [1, 2]\n", "a: 1\nb: [1, 2]\n", ""},
+		{"KeyOrder", "a: 1\nb: 2\n", "b: 2\na: 1\n", ""},
+		{"Formatting", "# A comment.\nlist: [1, 2]\nmap: {a: x}\n", "list:\n  - 1\n  - 2\nmap:\n  a: \"x\"\n", ""},
+		{"DifferentValue", "a: 1\nb: 2\n", "a: 1\nb: 3\n", "Expected values to be equal:\n a: 1\n-b: 2\n+b: 3\n"},
+		{"InvalidExpected", "a: [", "a: 1", "Expected value is not valid YAML:\nyaml: line 1: did not find expected node content\na: ["},
+		{"InvalidActual", "a: 1", "a: [", "Actual value is not valid YAML:\nyaml: line 1: did not find expected node content\na: ["},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tester := &testTester{T: t}
+			Equal(tester, test.expected, test.actual)
+			assert.Equal(t, test.failed, tester.failed)
+		})
+	}
+	tester := &testTester{T: t}
+	Equal(tester, "a: [", "a: 1", "config %s", "prod")
+	assert.Equal(t, "Expected value is not valid YAML:\nyaml: line 1: did not find expected node content\na: [\nconfig prod", tester.failed)
+}